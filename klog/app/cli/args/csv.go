@@ -0,0 +1,27 @@
+package args
+
+import (
+	"unicode/utf8"
+
+	"github.com/jotaen/klog/klog/app"
+)
+
+type CsvArgs struct {
+	Delimiter string `name:"delimiter" placeholder:"CHAR" help:"CSV only: The character that separates the fields. CHAR can be any single character, or 'tab'. Defaults to ','." default:","`
+}
+
+func (args *CsvArgs) CsvDelimiter() (rune, app.Error) {
+	if args.Delimiter == "tab" {
+		return '\t', nil
+	}
+	delimiter, size := utf8.DecodeRuneInString(args.Delimiter)
+	if size == 0 || size != len(args.Delimiter) || delimiter == '"' || delimiter == '\r' || delimiter == '\n' {
+		return 0, app.NewErrorWithCode(
+			app.GENERAL_ERROR,
+			"Invalid delimiter",
+			"The delimiter must be a single character (other than a quote or a line break), or 'tab'",
+			nil,
+		)
+	}
+	return delimiter, nil
+}
@@ -11,7 +11,7 @@ import (
 
 type FilterArgs struct {
 	// Date-related filters:
-	Date   klog.Date     `name:"date" placeholder:"DATE" group:"Filter Flags:" help:"Records at this date. DATE has to be in format YYYY-MM-DD or YYYY/MM/DD. E.g., '2024-01-31' or '2024/01/31'."`
+	Date   klog.Date     `name:"date" placeholder:"DATE" group:"Filter Flags:" help:"Records at this date. DATE has to be in format YYYY-MM-DD or YYYY/MM/DD, or a relative date such as 'today', 'yesterday', '-14d' (14 days ago) or '-2w' (2 weeks ago). E.g., '2024-01-31' or '2024/01/31'."`
 	Since  klog.Date     `name:"since" placeholder:"DATE" group:"Filter Flags:" help:"Records since this date (inclusive)."`
 	Until  klog.Date     `name:"until" placeholder:"DATE" group:"Filter Flags:" help:"Records until this date (inclusive)."`
 	Period period.Period `name:"period" placeholder:"PERIOD" group:"Filter Flags:" help:"Records within a calendar period. PERIOD has to be in format YYYY, YYYY-MM, YYYY-Www or YYYY-Qq. E.g., '2024', '2024-04', '2022-W21' or '2024-Q1'."`
@@ -34,14 +34,16 @@ type FilterArgs struct {
 	LastYear    bool `hidden:"" name:"last-year" group:"Filter Flags:" completion-enabled:"true"`
 
 	// General filters:
-	Tags   []klog.Tag `name:"tag" placeholder:"TAG" group:"Filter Flags:" help:"Records or entries that match these tags (either in the record summary or the entry summary). You can omit the leading '#'."`
-	Filter string     `name:"filter" placeholder:"EXPR" group:"Filter Flags:" help:"Records or entries that match this filter expression. Run 'klog info --filtering' to learn how expressions works."`
+	Tags   []klog.TagPattern `name:"tag" placeholder:"TAG" group:"Filter Flags:" help:"Records or entries that match these tags (either in the record summary or the entry summary). You can omit the leading '#'. You can use '*' as wildcard, e.g. 'client-*' or 'ticket=PROJ-*'."`
+	Filter string            `name:"filter" placeholder:"EXPR" group:"Filter Flags:" help:"Records or entries that match this filter expression. You can reference named filters from the config file, e.g. '@billable'. Run 'klog info --filtering' to learn how expressions works." completion-predictor:"named_filter"`
 
 	hasPartialRecordsWithShouldTotal bool          // Field only for internal use
 	singleShortHandFilter            period.Period // Field only for internal use
 }
 
-func (args *FilterArgs) ApplyFilter(now gotime.Time, rs []klog.Record) ([]klog.Record, app.Error) {
+// ApplyFilter filters the records. References to named filters in the filter
+// expression are resolved from `namedFilters`.
+func (args *FilterArgs) ApplyFilter(now gotime.Time, namedFilters filter.NamedFilters, rs []klog.Record) ([]klog.Record, app.Error) {
 	var predicates = []filter.Predicate{}
 
 	// Closed date-range filters:
@@ -110,14 +112,14 @@ func (args *FilterArgs) ApplyFilter(now gotime.Time, rs []klog.Record) ([]klog.R
 
 	// Tag filters:
 	for _, t := range args.Tags {
-		predicates = append(predicates, filter.HasTag{
-			Tag: t,
+		predicates = append(predicates, filter.HasTagPattern{
+			Pattern: t,
 		})
 	}
 
 	// Filter expression:
 	if args.Filter != "" {
-		filterPredicate, err := filter.Parse(args.Filter)
+		filterPredicate, err := filter.ParseWithNamedFilters(args.Filter, klog.NewDateFromGo(now), namedFilters)
 		if err != nil {
 			return nil, app.NewErrorWithCode(
 				app.GENERAL_ERROR,
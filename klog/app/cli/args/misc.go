@@ -65,3 +65,11 @@ func (args *DecimalArgs) Apply(ctx *app.Context) {
 		})
 	}
 }
+
+type OutputArgs struct {
+	Output string `name:"output" placeholder:"FORMAT" help:"Output format. FORMAT can be 'text' (default) or 'json'." enum:"text,json," default:"text"`
+}
+
+func (args *OutputArgs) IsJson() bool {
+	return args.Output == "json"
+}
@@ -19,6 +19,17 @@ func (args *WarnArgs) PrintWarnings(ctx app.Context, records []klog.Record, addi
 	}
 }
 
+// PrintWarningsToStderr is like `PrintWarnings`, but prints to stderr. That
+// way, the warnings don’t interfere with output that is meant for further
+// processing, e.g. documents or data formats.
+func (args *WarnArgs) PrintWarningsToStderr(ctx app.Context, records []klog.Record, additionalWarnings []service.UsageWarning) {
+	styler, _ := ctx.Serialise()
+	warnings := args.GatherWarnings(ctx, records, additionalWarnings)
+	for _, w := range warnings {
+		ctx.PrintErr(prettify.PrettifyWarning(w, styler))
+	}
+}
+
 func (args *WarnArgs) GatherWarnings(ctx app.Context, records []klog.Record, additionalWarnings []service.UsageWarning) []string {
 	if args.NoWarn {
 		return nil
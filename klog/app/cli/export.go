@@ -0,0 +1,88 @@
+package cli
+
+import (
+	"strings"
+
+	"github.com/jotaen/klog/klog/app"
+	"github.com/jotaen/klog/klog/app/cli/args"
+	"github.com/jotaen/klog/klog/parser/csv"
+	"github.com/jotaen/klog/klog/parser/ical"
+	"github.com/jotaen/klog/klog/parser/org"
+	"github.com/jotaen/klog/klog/parser/timeclock"
+	"github.com/jotaen/klog/klog/parser/timewarrior"
+)
+
+type Export struct {
+	Format     string `name:"format" placeholder:"FORMAT" help:"The format of the output data. FORMAT can be 'csv' (default), 'ics', 'org', 'timeclock' or 'timewarrior'." enum:"csv,ics,org,timeclock,timewarrior," default:"csv"`
+	NoHeader   bool   `name:"no-header" help:"CSV only: Omit the header row with the column names."`
+	AccountTag string `name:"account-tag" placeholder:"TAG" help:"Timeclock only: Derive the account name from this tag (instead of from the first tag)."`
+	args.CsvArgs
+	args.NowArgs
+	args.FilterArgs
+	args.SortArgs
+	args.InputFilesArgs
+}
+
+func (opt *Export) Help() string {
+	return `
+Converts records to other data formats, e.g. for processing them in other tools.
+The output is printed to stdout, so you can redirect it into a file.
+
+Supported formats:
+  - 'csv': Comma-separated values, with one row per entry. The columns are: ` + "'" + strings.Join(csv.Columns, "', '") + "'" + `.
+    For range entries, 'start' and 'end' contain the times as in the file (including shift markers such as '<23:00' or '1:00>').
+    Open ranges have an empty 'end' value, unless you specify '--now'. The 'tags' column contains all tags that apply to the entry (from both the record summary and the entry summary).
+  - 'ics': iCalendar data, e.g. for importing into calendar applications. Every range entry yields an event, with the entry summary as title and the tags as categories.
+    Duration entries yield all-day events. Open ranges are omitted, unless you specify '--now'.
+    The times are “floating”, i.e. they are not bound to a particular time zone.
+  - 'org': Headings with clock lines for Emacs org-mode. Every range entry yields a closed clock, and every open range yields a running clock.
+    The entry summary becomes the heading, and clocks with the same heading are grouped together. The tags of the record summary become org tags. Duration entries are omitted.
+  - 'timeclock': Check-in and check-out lines, as understood by accounting tools such as hledger. Every range entry yields an 'i'/'o' pair.
+    The account name is derived from the first tag of the entry (or of the record), or from the tag specified via '--account-tag'.
+    The levels of hierarchical tags and tag values become sub-accounts, e.g. '#acme/backend' yields 'acme:backend', and '#client=acme' yields 'client:acme'.
+    Entries without such tag are booked on the account '` + timeclock.DefaultAccount + `'. The entry summary becomes the description.
+    Duration entries yield pairs as well, which start after the latest range of the record (or at midnight, if there are no ranges), so that they don’t overlap.
+    Negative durations are omitted, and so are open ranges, unless you specify '--now'.
+  - 'timewarrior': The interval format of Timewarrior’s data files. Every range entry yields a closed interval, and every open range yields an open interval.
+    The tags of the entry become the interval tags, and the entry summary becomes the annotation. Duration entries are omitted.
+
+Records without entries don’t appear in the output.
+`
+}
+
+func (opt *Export) Run(ctx app.Context) app.Error {
+	records, err := ctx.ReadInputs(opt.File...)
+	if err != nil {
+		return err
+	}
+	now := ctx.Now()
+	records, fErr := opt.ApplyFilter(now, ctx.Config().NamedFilters.UnwrapOr(nil), records)
+	if fErr != nil {
+		return fErr
+	}
+	nErr := opt.ApplyNow(now, records...)
+	if nErr != nil {
+		return nErr
+	}
+	records = opt.ApplySort(records)
+	switch opt.Format {
+	case "ics":
+		ctx.Print(ical.ToIcal(records, now))
+	case "org":
+		ctx.Print(org.ToOrg(records))
+	case "timeclock":
+		ctx.Print(timeclock.ToTimeclock(records, opt.AccountTag))
+	case "timewarrior":
+		ctx.Print(timewarrior.ToTimewarrior(records, now.Location()))
+	default:
+		delimiter, dErr := opt.CsvDelimiter()
+		if dErr != nil {
+			return dErr
+		}
+		ctx.Print(csv.ToCsv(records, csv.Options{
+			Delimiter:  delimiter,
+			OmitHeader: opt.NoHeader,
+		}))
+	}
+	return nil
+}
@@ -0,0 +1,193 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jotaen/klog/klog"
+	"github.com/jotaen/klog/klog/app/cli/args"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExportCsv(t *testing.T) {
+	state, err := NewTestingContext()._SetRecords(`
+2018-01-31 (8h!)
+Hello #world
+    <23:00 - 1:00 Night shift
+    -30m Break
+
+2018-02-01
+    9:00 - ? #project
+`)._Run((&Export{CsvArgs: args.CsvArgs{Delimiter: ","}}).Run)
+	require.Nil(t, err)
+	assert.Equal(t, `
+date,type,start,end,duration_mins,record_summary,entry_summary,tags
+2018-01-31,range,<23:00,1:00,120,Hello #world,Night shift,#world
+2018-01-31,duration,,,-30,Hello #world,Break,#world
+2018-02-01,open,9:00,,0,,#project,#project
+`, state.printBuffer)
+}
+
+func TestExportCsvWithOptions(t *testing.T) {
+	state, err := NewTestingContext()._SetRecords(`
+2018-01-31
+    1h Foo
+`)._Run((&Export{CsvArgs: args.CsvArgs{Delimiter: "tab"}, NoHeader: true}).Run)
+	require.Nil(t, err)
+	assert.Equal(t, "\n2018-01-31\tduration\t\t\t60\t\tFoo\t\n", state.printBuffer)
+}
+
+func TestExportCsvWithFilterSortAndNow(t *testing.T) {
+	state, err := NewTestingContext()._SetRecords(`
+2018-01-30
+    1h #a
+
+2018-01-31
+    2h #b
+    9:00 - ? #a
+
+2018-02-01
+    3h #a
+`)._SetNow(2018, 1, 31, 10, 30)._Run((&Export{
+		CsvArgs:    args.CsvArgs{Delimiter: ";"},
+		NoHeader:   true,
+		NowArgs:    args.NowArgs{Now: true},
+		SortArgs:   args.SortArgs{Sort: "desc"},
+		FilterArgs: args.FilterArgs{Tags: []klog.TagPattern{klog.NewTagPatternOrPanic("a", "")}, Until: klog.Ɀ_Date_(2018, 1, 31)},
+	}).Run)
+	require.Nil(t, err)
+	assert.Equal(t, `
+2018-01-31;range;9:00;10:30;90;;#a;#a
+2018-01-30;duration;;;60;;#a;#a
+`, state.printBuffer)
+}
+
+func TestExportAppliesNowAfterFilteringLikeOtherCommands(t *testing.T) {
+	ctx := NewTestingContext()._SetRecords(`
+2018-01-31
+    2h #a
+    9:00 - ? #a
+`)._SetNow(2018, 1, 31, 10, 30)
+	filterArgs := args.FilterArgs{Filter: "duration>1h"}
+
+	exported, err := ctx._Run((&Export{
+		CsvArgs:    args.CsvArgs{Delimiter: ";"},
+		NoHeader:   true,
+		NowArgs:    args.NowArgs{Now: true},
+		FilterArgs: filterArgs,
+	}).Run)
+	require.Nil(t, err)
+	assert.Equal(t, "\n2018-01-31;duration;;;120;;#a;#a\n", exported.printBuffer)
+
+	total, err := ctx._Run((&Total{NowArgs: args.NowArgs{Now: true}, FilterArgs: filterArgs}).Run)
+	require.Nil(t, err)
+	assert.Contains(t, total.printBuffer, "Total: 2h")
+}
+
+func TestExportCsvRejectsInvalidDelimiter(t *testing.T) {
+	for _, d := range []string{"", ";;", `"`, "\n"} {
+		_, err := NewTestingContext()._SetRecords(`
+2018-01-31
+    1h
+`)._Run((&Export{CsvArgs: args.CsvArgs{Delimiter: d}}).Run)
+		require.Error(t, err)
+		assert.Equal(t, "Invalid delimiter", err.Error())
+	}
+}
+
+func TestExportIcal(t *testing.T) {
+	state, err := NewTestingContext()._SetRecords(`
+2018-01-30
+    1h #a
+
+2018-01-31
+    9:00 - 10:00 Meeting #b
+    10:00 - ?
+`)._SetNow(2018, 1, 31, 10, 30)._Run((&Export{
+		Format:     "ics",
+		FilterArgs: args.FilterArgs{Date: klog.Ɀ_Date_(2018, 1, 31)},
+	}).Run)
+	require.Nil(t, err)
+	assert.Contains(t, state.printBuffer, "BEGIN:VCALENDAR\r\n")
+	assert.Equal(t, 1, strings.Count(state.printBuffer, "BEGIN:VEVENT"))
+	assert.Contains(t, state.printBuffer, "DTSTART:20180131T090000\r\n"+
+		"DTEND:20180131T100000\r\n"+
+		"SUMMARY:Meeting #b\r\n"+
+		"CATEGORIES:b\r\n")
+}
+
+func TestExportTimewarrior(t *testing.T) {
+	state, err := NewTestingContext()._SetRecords(`
+2018-01-31
+    1h #a
+    9:00 - 10:00 Meeting #b
+    10:00 - ?
+`)._SetNow(2018, 1, 31, 10, 30)._Run((&Export{
+		Format: "timewarrior",
+	}).Run)
+	require.Nil(t, err)
+	assert.Equal(t, `
+inc 20180131T090000Z - 20180131T100000Z # b # "Meeting #b"
+inc 20180131T100000Z
+`, state.printBuffer)
+}
+
+func TestExportOrg(t *testing.T) {
+	state, err := NewTestingContext()._SetRecords(`
+2018-01-31
+#acme
+    1h Omitted
+    9:00 - 10:00 Meeting
+    10:00 - ? Meeting
+`)._Run((&Export{
+		Format: "org",
+	}).Run)
+	require.Nil(t, err)
+	assert.Equal(t, `
+* Meeting :acme:
+  :LOGBOOK:
+  CLOCK: [2018-01-31 Wed 09:00]--[2018-01-31 Wed 10:00] =>  1:00
+  CLOCK: [2018-01-31 Wed 10:00]
+  :END:
+`, state.printBuffer)
+}
+
+func TestExportTimeclock(t *testing.T) {
+	state, err := NewTestingContext()._SetRecords(`
+2018-01-31
+#acme
+    1h Support
+    9:00 - 10:00 Meeting #client=x
+    10:00 - ?
+`)._SetNow(2018, 1, 31, 10, 30)._Run((&Export{
+		Format:     "timeclock",
+		AccountTag: "acme",
+		NowArgs:    args.NowArgs{Now: true},
+	}).Run)
+	require.Nil(t, err)
+	assert.Equal(t, `
+i 2018-01-31 10:30:00 acme  Support
+o 2018-01-31 11:30:00
+i 2018-01-31 09:00:00 acme  Meeting #client=x
+o 2018-01-31 10:00:00
+i 2018-01-31 10:00:00 acme
+o 2018-01-31 10:30:00
+`, state.printBuffer)
+}
+
+func TestExportCsvCanBeImportedAgain(t *testing.T) {
+	records := `2018-01-31
+    1h Work #acme/backend
+    2h #acme #ticket=12
+`
+	exported, err := NewTestingContext()._SetRecords(records)._Run((&Export{CsvArgs: args.CsvArgs{Delimiter: ","}}).Run)
+	require.Nil(t, err)
+
+	imported, err := NewTestingContext()._SetReloadAfterWrite()._SetRecords("")._SetRawInput(exported.printBuffer)._Run((&Import{
+		Format:  "csv",
+		CsvArgs: args.CsvArgs{Delimiter: ","},
+	}).Run)
+	require.Nil(t, err)
+	assert.Equal(t, records, imported.writtenFileContents)
+}
@@ -0,0 +1,185 @@
+package cli
+
+import (
+	"math"
+	"strings"
+
+	"github.com/jotaen/klog/klog"
+	"github.com/jotaen/klog/klog/app"
+	"github.com/jotaen/klog/klog/app/cli/args"
+	"github.com/jotaen/klog/klog/app/cli/prettify"
+	"github.com/jotaen/klog/klog/service"
+	tf "github.com/jotaen/klog/lib/terminalformat"
+)
+
+type Heatmap struct {
+	args.FilterArgs
+	args.NowArgs
+	args.DecimalArgs
+	args.WarnArgs
+	args.NoStyleArgs
+	args.InputFilesArgs
+}
+
+func (opt *Heatmap) Help() string {
+	return `
+Every column is a calendar week (from Monday to Sunday), and every row is a weekday.
+The calendar spans from the first to the last date of the records, or the requested period if you use a period flag (e.g. '--period 2024' or '--last-quarter').
+
+The daily totals are divided into 4 levels of intensity, which are scaled to the highest daily total.
+The legend below the calendar shows the upper bound of each level.
+Days without any time are shown as '·', whereas the levels are shown in colours from blue (lowest) to red (highest).
+The levels are also shown with increasingly dense glyphs, so that they are discernible without colours, too.
+`
+}
+
+var heatmapGlyphs = []string{"·", "░", "▒", "▓", "█"}
+
+// heatmapColours are the colours of the levels, in the same order as the glyphs.
+var heatmapColours = []tf.Colour{tf.TEXT_SUBDUED, tf.BLUE_DARK, tf.GREEN, tf.YELLOW, tf.RED}
+
+func (opt *Heatmap) Run(ctx app.Context) app.Error {
+	opt.DecimalArgs.Apply(&ctx)
+	opt.NoStyleArgs.Apply(&ctx)
+	styler, serialiser := ctx.Serialise()
+	records, err := ctx.ReadInputs(opt.File...)
+	if err != nil {
+		return err
+	}
+	now := ctx.Now()
+	records, fErr := opt.ApplyFilter(now, ctx.Config().NamedFilters.UnwrapOr(nil), records)
+	if fErr != nil {
+		return fErr
+	}
+	if len(records) == 0 {
+		return nil
+	}
+	nErr := opt.ApplyNow(now, records...)
+	if nErr != nil {
+		return nErr
+	}
+	records = service.Sort(records, true)
+	since, until := records[0].Date(), records[len(records)-1].Date()
+	if singlePeriod := opt.FilterArgs.SinglePeriodRequested(); singlePeriod != nil {
+		since, until = singlePeriod.Since(), singlePeriod.Until()
+	}
+
+	totals := make(map[string]klog.Duration)
+	maxTotal := 0
+	for _, r := range records {
+		key := r.Date().ToString()
+		if totals[key] == nil {
+			totals[key] = klog.NewDuration(0, 0)
+		}
+		totals[key] = totals[key].Plus(service.Total(r))
+		if totals[key].InMinutes() > maxTotal {
+			maxTotal = totals[key].InMinutes()
+		}
+	}
+	bounds := heatmapBounds(maxTotal)
+
+	// The calendar starts at the Monday of the first week, and ends at the
+	// Sunday of the last week.
+	firstMonday := since.PlusDays(-(since.Weekday() - 1))
+	numberOfWeeks := 0
+	for d := firstMonday; !d.IsAfterOrEqual(until.PlusDays(1)); d = d.PlusDays(7) {
+		numberOfWeeks++
+	}
+
+	// Header with the month names, at the week where the month begins. If
+	// that column is occupied by the previous label, the label is shifted
+	// to the next free column.
+	const prefixWidth = 4
+	header := []rune(strings.Repeat(" ", prefixWidth+2*numberOfWeeks))
+	nextFreeColumn := 0
+	for week := 0; week < numberOfWeeks; week++ {
+		for day := 0; day < 7; day++ {
+			date := firstMonday.PlusDays(7*week + day)
+			isFirstColumn := week == 0 && date.IsEqualTo(since)
+			if !isFirstColumn && (date.Day() != 1 || !isInDateRange(date, since, until)) {
+				continue
+			}
+			column := prefixWidth + 2*week
+			for column < nextFreeColumn {
+				column += 2
+			}
+			if column >= prefixWidth+2*numberOfWeeks {
+				// There is no column left for the label.
+				continue
+			}
+			label := []rune(prettify.PrettyMonth(date.Month())[:3])
+			for len(header) < column+len(label) {
+				header = append(header, ' ')
+			}
+			copy(header[column:], label)
+			nextFreeColumn = column + len(label) + 1
+		}
+	}
+	ctx.Print(strings.TrimRight(string(header), " ") + "\n")
+
+	// Rows with the weekdays.
+	for day := 0; day < 7; day++ {
+		row := prettify.PrettyDay(day + 1)[:3]
+		for week := 0; week < numberOfWeeks; week++ {
+			date := firstMonday.PlusDays(7*week + day)
+			row += " "
+			if !isInDateRange(date, since, until) {
+				row += " "
+				continue
+			}
+			row += heatmapCell(styler, heatmapLevel(bounds, totals[date.ToString()]))
+		}
+		ctx.Print(strings.TrimRight(row, " ") + "\n")
+	}
+
+	// Legend
+	legend := "\n" + heatmapCell(styler, 0) + " " + serialiser.Duration(klog.NewDuration(0, 0))
+	for i, bound := range bounds {
+		legend += "  " + heatmapCell(styler, i+1) + " ≤" + serialiser.Duration(klog.NewDuration(0, bound))
+	}
+	ctx.Print(legend + "\n")
+	opt.WarnArgs.PrintWarnings(ctx, records, []service.UsageWarning{opt.NowArgs.GetWarning()})
+	return nil
+}
+
+// heatmapBounds returns the (inclusive) upper bounds in minutes for the
+// levels of intensity, which divide the range up to the maximum evenly.
+// If the maximum is not positive, there are no levels.
+func heatmapBounds(maxMins int) []int {
+	if maxMins <= 0 {
+		return nil
+	}
+	levels := len(heatmapGlyphs) - 1
+	var bounds []int
+	for i := 1; i <= levels; i++ {
+		bound := int(math.Ceil(float64(maxMins*i) / float64(levels)))
+		if len(bounds) > 0 && bound <= bounds[len(bounds)-1] {
+			// For very small maximums, the levels would overlap.
+			continue
+		}
+		bounds = append(bounds, bound)
+	}
+	return bounds
+}
+
+// heatmapLevel returns the level of intensity of the total, where 0 means
+// that there is no (positive) time.
+func heatmapLevel(bounds []int, total klog.Duration) int {
+	if total == nil || total.InMinutes() <= 0 {
+		return 0
+	}
+	for i, bound := range bounds {
+		if total.InMinutes() <= bound {
+			return i + 1
+		}
+	}
+	return len(bounds)
+}
+
+func heatmapCell(styler tf.Styler, level int) string {
+	return styler.Props(tf.StyleProps{Color: heatmapColours[level]}).Format(heatmapGlyphs[level])
+}
+
+func isInDateRange(d klog.Date, since klog.Date, until klog.Date) bool {
+	return d.IsAfterOrEqual(since) && until.IsAfterOrEqual(d)
+}
@@ -0,0 +1,88 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jotaen/klog/klog"
+	"github.com/jotaen/klog/klog/app/cli/args"
+	"github.com/jotaen/klog/klog/service/period"
+	tf "github.com/jotaen/klog/lib/terminalformat"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHeatmapOfEmptyInput(t *testing.T) {
+	state, err := NewTestingContext()._SetRecords(``)._Run((&Heatmap{}).Run)
+	require.Nil(t, err)
+	assert.Equal(t, "", state.printBuffer)
+}
+
+func TestHeatmap(t *testing.T) {
+	state, err := NewTestingContext()._SetRecords(`
+2024-01-31
+	8h
+
+2024-02-01
+	2h
+
+2024-02-03
+	4h30m
+
+2024-02-06
+	-1h
+
+2024-02-14
+	6h
+`)._Run((&Heatmap{}).Run)
+	require.Nil(t, err)
+	assert.Equal(t, `
+    Jan Feb
+Mon   · ·
+Tue   · ·
+Wed █ · ▓
+Thu ░ ·
+Fri · ·
+Sat ▓ ·
+Sun · ·
+
+· 0m  ░ ≤2h  ▒ ≤4h  ▓ ≤6h  █ ≤8h
+`, state.printBuffer)
+}
+
+func TestHeatmapWithPeriod(t *testing.T) {
+	state, err := NewTestingContext()._SetRecords(`
+2023-12-31
+	10h
+
+2024-03-04
+	1h
+
+2024-03-10
+	4m
+`)._Run((&Heatmap{FilterArgs: args.FilterArgs{Period: period.NewMonthFromDate(klog.Ɀ_Date_(2024, 3, 1)).Period()}}).Run)
+	require.Nil(t, err)
+	assert.Equal(t, `
+    Mar
+Mon   █ · · ·
+Tue   · · · ·
+Wed   · · · ·
+Thu   · · · ·
+Fri · · · · ·
+Sat · · · · ·
+Sun · ░ · · ·
+
+· 0m  ░ ≤15m  ▒ ≤30m  ▓ ≤45m  █ ≤1h
+`, state.printBuffer)
+}
+
+func TestHeatmapLevelsHaveDistinctColours(t *testing.T) {
+	styler := tf.NewStyler(tf.COLOUR_THEME_BASIC)
+	colourCodes := make(map[string]bool)
+	for level, glyph := range heatmapGlyphs {
+		cell := heatmapCell(styler, level)
+		require.Contains(t, cell, glyph)
+		colourCodes[strings.Split(cell, glyph)[0]] = true
+	}
+	assert.Len(t, colourCodes, len(heatmapGlyphs))
+}
@@ -0,0 +1,106 @@
+package helper
+
+import (
+	"fmt"
+
+	"github.com/jotaen/klog/klog"
+	"github.com/jotaen/klog/klog/app"
+	"github.com/jotaen/klog/klog/parser"
+	"github.com/jotaen/klog/klog/parser/reconciling"
+	"github.com/jotaen/klog/klog/service"
+)
+
+type ImportOpts struct {
+	ReconcileOpts
+
+	// SkipExistingEntries skips entries that already exist in the target record,
+	// so that importing the same data multiple times doesn’t yield duplicates.
+	SkipExistingEntries bool
+
+	// Warnings are printed in addition to the warnings about the records,
+	// e.g. to inform about input data that couldn’t be imported.
+	Warnings []service.UsageWarning
+}
+
+// Import merges records into the target file, one after the other. If there
+// already is a record at the respective date, the entries are appended to it.
+// Otherwise, it creates a new record. Dates and times are formatted according
+// to the user’s preferences, or to the prevalent style of the target file.
+func Import(ctx app.Context, opts ImportOpts, records []klog.Record) app.Error {
+	dateFormat := reconciling.ReformatAutoStyle[klog.DateFormat]()
+	ctx.Config().DateUseDashes.Unwrap(func(x bool) {
+		dateFormat = reconciling.ReformatExplicitly(klog.DateFormat{UseDashes: x})
+	})
+	timeFormat := reconciling.ReformatAutoStyle[klog.TimeFormat]()
+	ctx.Config().TimeUse24HourClock.Unwrap(func(x bool) {
+		timeFormat = reconciling.ReformatExplicitly(klog.TimeFormat{Use24HourClock: x})
+	})
+
+	var importedRecords []klog.Record
+	var lastResult *reconciling.Result
+	for _, r := range records {
+		hasAppendedEntries := false
+		additionalData := reconciling.AdditionalData{Summary: r.Summary()}
+		if r.ShouldTotal().InMinutes() != 0 {
+			additionalData.ShouldTotal = r.ShouldTotal()
+		}
+		result, err := ctx.ReconcileFile(
+			opts.OutputFileArgs.File,
+			[]reconciling.Creator{
+				reconciling.NewReconcilerAtRecord(r.Date()),
+				reconciling.NewReconcilerForNewRecord(r.Date(), dateFormat, additionalData),
+			},
+			func(reconciler *reconciling.Reconciler) error {
+				existingEntries := make(map[string]bool)
+				for _, e := range reconciler.Record.Entries() {
+					existingEntries[entryKey(e)] = true
+				}
+				for _, e := range r.Entries() {
+					if opts.SkipExistingEntries && existingEntries[entryKey(e)] {
+						continue
+					}
+					hasAppendedEntries = true
+					aErr := reconciler.AppendEntryFrom(e, timeFormat)
+					if aErr != nil {
+						return fmt.Errorf("%s: %w", r.Date().ToString(), aErr)
+					}
+				}
+				return nil
+			},
+		)
+		if err != nil {
+			return err
+		}
+		lastResult = result
+		if hasAppendedEntries || len(r.Entries()) == 0 {
+			importedRecords = append(importedRecords, result.Record)
+		}
+	}
+	if lastResult == nil {
+		opts.WarnArgs.PrintWarnings(ctx, nil, opts.Warnings)
+		return nil
+	}
+	if len(importedRecords) == 0 {
+		ctx.Print("Nothing to import, all entries exist already.\n")
+		opts.WarnArgs.PrintWarnings(ctx, nil, opts.Warnings)
+		return nil
+	}
+
+	_, serialiser := ctx.Serialise()
+	ctx.Print("\n" + parser.SerialiseRecords(serialiser, importedRecords...).ToString() + "\n")
+	opts.WarnArgs.PrintWarnings(ctx, lastResult.AllRecords, opts.Warnings)
+	return nil
+}
+
+// entryKey returns an identifier for the entry, which only depends on the
+// entry’s values and its summary, but not on the formatting.
+func entryKey(e klog.Entry) string {
+	value := klog.Unbox(&e, func(tr klog.Range) string {
+		return fmt.Sprintf("range:%d-%d", tr.Start().MidnightOffset().InMinutes(), tr.End().MidnightOffset().InMinutes())
+	}, func(d klog.Duration) string {
+		return fmt.Sprintf("duration:%d", d.InMinutes())
+	}, func(o klog.OpenRange) string {
+		return fmt.Sprintf("open_range:%d", o.Start().MidnightOffset().InMinutes())
+	})
+	return value + "\n" + parser.SummaryText(e.Summary()).ToString()
+}
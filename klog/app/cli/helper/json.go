@@ -0,0 +1,44 @@
+package helper
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"github.com/jotaen/klog/klog"
+	"github.com/jotaen/klog/klog/app"
+	"github.com/jotaen/klog/klog/service"
+)
+
+// TotalsView is the JSON representation of evaluated total times.
+type TotalsView struct {
+	Total           string `json:"total"`
+	TotalMins       int    `json:"total_mins"`
+	ShouldTotal     string `json:"should_total"`
+	ShouldTotalMins int    `json:"should_total_mins"`
+	Diff            string `json:"diff"`
+	DiffMins        int    `json:"diff_mins"`
+}
+
+func NewTotalsView(total klog.Duration, should klog.Duration) TotalsView {
+	diff := service.Diff(should, total)
+	return TotalsView{
+		Total:           total.ToString(),
+		TotalMins:       total.InMinutes(),
+		ShouldTotal:     should.ToString(),
+		ShouldTotalMins: should.InMinutes(),
+		Diff:            diff.ToStringWithSign(),
+		DiffMins:        diff.InMinutes(),
+	}
+}
+
+// PrintJson prints the data as JSON, on a single line.
+func PrintJson(ctx app.Context, data any) {
+	buffer := new(bytes.Buffer)
+	enc := json.NewEncoder(buffer)
+	enc.SetEscapeHTML(false)
+	err := enc.Encode(data)
+	if err != nil {
+		panic(err) // This should never happen
+	}
+	ctx.Print(buffer.String())
+}
@@ -0,0 +1,167 @@
+package cli
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/jotaen/klog/klog"
+	"github.com/jotaen/klog/klog/app"
+	"github.com/jotaen/klog/klog/app/cli/args"
+	"github.com/jotaen/klog/klog/app/cli/helper"
+	"github.com/jotaen/klog/klog/app/cli/prettify"
+	"github.com/jotaen/klog/klog/service"
+	tf "github.com/jotaen/klog/lib/terminalformat"
+)
+
+type Histogram struct {
+	PerWeekday bool `name:"per-weekday" short:"w" help:"Break down the time by weekday, with one column per weekday."`
+	args.FilterArgs
+	args.NowArgs
+	args.DecimalArgs
+	args.WarnArgs
+	args.NoStyleArgs
+	args.OutputArgs
+	args.InputFilesArgs
+}
+
+type histogramJsonView struct {
+	Hours    []histogramHourJsonView `json:"hours"`
+	Warnings []string                `json:"warnings"`
+}
+
+type histogramHourJsonView struct {
+	Hour      int                        `json:"hour"`
+	Total     string                     `json:"total"`
+	TotalMins int                        `json:"total_mins"`
+	Weekdays  []histogramWeekdayJsonView `json:"weekdays,omitempty"`
+}
+
+type histogramWeekdayJsonView struct {
+	Weekday   string `json:"weekday"`
+	Total     string `json:"total"`
+	TotalMins int    `json:"total_mins"`
+}
+
+func (opt *Histogram) Help() string {
+	return `
+It sums up the time of all time ranges by hour of day, so that you can see at what times of the day you usually work.
+Every time range is split at the full hours, e.g. '8:45 - 10:15' yields 15 minutes at 8:00, 60 minutes at 9:00 and 15 minutes at 10:00.
+Shifted times count towards the previous or next day, e.g. '<23:00 - 1:00' yields 1 hour on the previous day.
+
+Durations (e.g., '2h') are not taken into account, since they don’t have a time of day.
+Open-ended time ranges (e.g., '8:00 - ?') are only taken into account if you use the '--now' flag.
+
+With '--per-weekday', the time is broken down by weekday, with one column per weekday.
+
+With '--output json', the result is printed as JSON object, which contains all 24 hours of day.
+`
+}
+
+func (opt *Histogram) Run(ctx app.Context) app.Error {
+	opt.DecimalArgs.Apply(&ctx)
+	opt.NoStyleArgs.Apply(&ctx)
+	styler, serialiser := ctx.Serialise()
+	records, err := ctx.ReadInputs(opt.File...)
+	if err != nil {
+		return err
+	}
+	now := ctx.Now()
+	records, fErr := opt.ApplyFilter(now, ctx.Config().NamedFilters.UnwrapOr(nil), records)
+	if fErr != nil {
+		return fErr
+	}
+	nErr := opt.ApplyNow(now, records...)
+	if nErr != nil {
+		return nErr
+	}
+	histogram := service.NewTimeOfDayHistogram(records...)
+	byHour := histogram.ByHour()
+	warnings := []service.UsageWarning{opt.NowArgs.GetWarning()}
+	if opt.IsJson() {
+		view := histogramJsonView{
+			Hours:    []histogramHourJsonView{},
+			Warnings: opt.WarnArgs.GatherWarnings(ctx, records, warnings),
+		}
+		for hour, mins := range byHour {
+			total := klog.NewDuration(0, mins)
+			hourView := histogramHourJsonView{Hour: hour, Total: total.ToString(), TotalMins: total.InMinutes()}
+			if opt.PerWeekday {
+				for weekday := range histogram {
+					weekdayTotal := klog.NewDuration(0, histogram[weekday][hour])
+					hourView.Weekdays = append(hourView.Weekdays, histogramWeekdayJsonView{
+						Weekday:   strings.ToLower(prettify.PrettyDay(weekday + 1)),
+						Total:     weekdayTotal.ToString(),
+						TotalMins: weekdayTotal.InMinutes(),
+					})
+				}
+			}
+			view.Hours = append(view.Hours, hourView)
+		}
+		helper.PrintJson(ctx, view)
+		return nil
+	}
+
+	// Only print the hours from the first to the last one with any time.
+	firstHour, lastHour, maxMins := -1, -1, 0
+	for hour, mins := range byHour {
+		if mins == 0 {
+			continue
+		}
+		if firstHour == -1 {
+			firstHour = hour
+		}
+		lastHour = hour
+		if mins > maxMins {
+			maxMins = mins
+		}
+	}
+	if firstHour == -1 {
+		opt.WarnArgs.PrintWarnings(ctx, records, warnings)
+		return nil
+	}
+
+	hourLabel := func(hour int) string {
+		return styler.Props(tf.StyleProps{Color: tf.TEXT_SUBDUED}).Format(fmt.Sprintf("%d:00", hour))
+	}
+	if opt.PerWeekday {
+		table := tf.NewTable(9, " ")
+		table.Skip(1)
+		for weekday := range histogram {
+			table.CellR(prettify.PrettyDay(weekday + 1)[:3])
+		}
+		table.CellR("   Total")
+		for hour := firstHour; hour <= lastHour; hour++ {
+			table.CellR(hourLabel(hour))
+			for weekday := range histogram {
+				if histogram[weekday][hour] == 0 {
+					table.Skip(1)
+					continue
+				}
+				table.CellR(serialiser.Duration(klog.NewDuration(0, histogram[weekday][hour])))
+			}
+			table.CellR(serialiser.Duration(klog.NewDuration(0, byHour[hour])))
+		}
+		table.Collect(ctx.Print)
+	} else {
+		table := tf.NewTable(3, " ")
+		for hour := firstHour; hour <= lastHour; hour++ {
+			table.CellR(hourLabel(hour))
+			table.CellR(serialiser.Duration(klog.NewDuration(0, byHour[hour])))
+			table.CellL(" " + renderHistogramBar(maxMins, byHour[hour]))
+		}
+		table.Collect(ctx.Print)
+	}
+	opt.WarnArgs.PrintWarnings(ctx, records, warnings)
+	return nil
+}
+
+// renderHistogramBar renders a bar whose length is proportional to the
+// minutes, where the maximum yields the full width.
+func renderHistogramBar(maxMins int, mins int) string {
+	const width = 40
+	if mins <= 0 || maxMins <= 0 {
+		return ""
+	}
+	return strings.Repeat("▇", int(math.Ceil(float64(mins)*width/float64(maxMins))))
+}
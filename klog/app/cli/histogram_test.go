@@ -0,0 +1,62 @@
+package cli
+
+import (
+	"testing"
+
+	"github.com/jotaen/klog/klog/app/cli/args"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHistogramOfEmptyInput(t *testing.T) {
+	state, err := NewTestingContext()._SetRecords(``)._Run((&Histogram{}).Run)
+	require.Nil(t, err)
+	assert.Equal(t, "", state.printBuffer)
+}
+
+func TestHistogram(t *testing.T) {
+	state, err := NewTestingContext()._SetRecords(`
+2024-01-01
+	8:45 - 10:15
+	11:30 - 12:00
+	5h
+
+2024-01-02
+	9:00 - 10:00
+`)._Run((&Histogram{}).Run)
+	require.Nil(t, err)
+	assert.Equal(t, `
+ 8:00 15m  ▇▇▇▇▇                                   
+ 9:00  2h  ▇▇▇▇▇▇▇▇▇▇▇▇▇▇▇▇▇▇▇▇▇▇▇▇▇▇▇▇▇▇▇▇▇▇▇▇▇▇▇▇
+10:00 15m  ▇▇▇▇▇                                   
+11:00 30m  ▇▇▇▇▇▇▇▇▇▇                              
+`, state.printBuffer)
+}
+
+func TestHistogramPerWeekday(t *testing.T) {
+	state, err := NewTestingContext()._SetRecords(`
+2024-01-01
+	8:30 - 10:00
+
+2024-01-03
+	9:00 - 9:45
+
+2024-01-08
+	9:15 - 9:30
+`)._Run((&Histogram{PerWeekday: true}).Run)
+	require.Nil(t, err)
+	assert.Equal(t, `
+       Mon Tue Wed Thu Fri Sat Sun    Total
+8:00   30m                              30m
+9:00 1h15m     45m                       2h
+`, state.printBuffer)
+}
+
+func TestHistogramAsJson(t *testing.T) {
+	state, err := NewTestingContext()._SetRecords(`
+2024-01-01
+	23:30 - 24:00
+`)._Run((&Histogram{OutputArgs: args.OutputArgs{Output: "json"}}).Run)
+	require.Nil(t, err)
+	assert.Equal(t, "\n"+`{"hours":[{"hour":0,"total":"0m","total_mins":0},{"hour":1,"total":"0m","total_mins":0},{"hour":2,"total":"0m","total_mins":0},{"hour":3,"total":"0m","total_mins":0},{"hour":4,"total":"0m","total_mins":0},{"hour":5,"total":"0m","total_mins":0},{"hour":6,"total":"0m","total_mins":0},{"hour":7,"total":"0m","total_mins":0},{"hour":8,"total":"0m","total_mins":0},{"hour":9,"total":"0m","total_mins":0},{"hour":10,"total":"0m","total_mins":0},{"hour":11,"total":"0m","total_mins":0},{"hour":12,"total":"0m","total_mins":0},{"hour":13,"total":"0m","total_mins":0},{"hour":14,"total":"0m","total_mins":0},{"hour":15,"total":"0m","total_mins":0},{"hour":16,"total":"0m","total_mins":0},{"hour":17,"total":"0m","total_mins":0},{"hour":18,"total":"0m","total_mins":0},{"hour":19,"total":"0m","total_mins":0},{"hour":20,"total":"0m","total_mins":0},{"hour":21,"total":"0m","total_mins":0},{"hour":22,"total":"0m","total_mins":0},{"hour":23,"total":"30m","total_mins":30}],"warnings":null}`+"\n", state.printBuffer)
+}
@@ -0,0 +1,109 @@
+package cli
+
+import (
+	"github.com/jotaen/klog/klog"
+	"github.com/jotaen/klog/klog/app"
+	"github.com/jotaen/klog/klog/app/cli/args"
+	"github.com/jotaen/klog/klog/app/cli/helper"
+	"github.com/jotaen/klog/klog/parser/csv"
+	"github.com/jotaen/klog/klog/parser/ical"
+	"github.com/jotaen/klog/klog/parser/json"
+	"github.com/jotaen/klog/klog/parser/org"
+	"github.com/jotaen/klog/klog/parser/timewarrior"
+	"github.com/jotaen/klog/klog/service"
+)
+
+type Import struct {
+	Format     string            `name:"format" placeholder:"FORMAT" help:"The format of the input data. FORMAT can be 'json' (default), 'csv', 'ics', 'org' or 'timewarrior'." enum:"json,csv,ics,org,timewarrior," default:"json"`
+	From       string            `name:"from" placeholder:"FILE" type:"path" help:"Read the input data from this file. If absent, it reads from stdin."`
+	Columns    map[string]string `name:"columns" placeholder:"FIELD=COLUMN;..." help:"CSV only: Which column (as named in the header row) to read a field from. FIELD can be 'date', 'start', 'end', 'end_date', 'duration', 'duration_mins', 'description', 'tags' or 'project'. E.g.: 'date=Day;description=Task'."`
+	DateFormat string            `name:"date-format" placeholder:"FORMAT" help:"CSV only: The format of the dates, where 'YYYY' is the year, 'MM' the month and 'DD' the day. E.g.: 'MM/DD/YYYY' or 'DD.MM.YYYY'. Defaults to klog’s date format."`
+	args.CsvArgs
+	args.FilterArgs
+	args.NoStyleArgs
+	args.WarnArgs
+	args.OutputFileArgs
+}
+
+func (opt *Import) Help() string {
+	return `
+Reads records from another data format and adds them to the target file.
+The input data is either read from a file (via '--from'), or from stdin.
+
+Supported formats:
+  - 'json': The JSON structure as produced by 'klog json'. (So you can convert JSON back to .klg.)
+  - 'csv': Comma-separated values with a header row, e.g. as exported from other time-tracking tools (or from 'klog export').
+    Every row becomes an entry in the record at the row’s date: a time range if there is a start and end time, an open range if there is only a start time, or a duration entry otherwise.
+    Dates must be in klog’s notation (e.g. '2024-01-31'), unless you specify another format via '--date-format', e.g. 'MM/DD/YYYY' for Toggl or 'DD.MM.YYYY' for Clockify.
+    Common column names are recognised automatically (e.g. 'Start date', 'Start time', 'End time', 'Duration', 'Description', 'Tags' or 'Project'). Use '--columns' to specify other ones.
+    Durations can be given as 'h:mm', 'h:mm:ss', decimal hours (e.g. '1.5'), or in klog notation. Tags can be a comma-separated list or in klog notation. The project becomes a tag as well.
+  - 'ics': iCalendar data, e.g. as exported from calendar applications. Every event becomes a time range entry in the record at the event’s start date.
+    The event title becomes the entry summary, and the event categories become tags.
+    All-day events and cancelled events are skipped. Events that span more than two days are skipped as well, and there is a warning about each of them.
+    Recurring events are only imported at their first occurrence.
+  - 'org': Clock lines from Emacs org-mode files, e.g. 'CLOCK: [2024-01-01 Mon 09:00]--[2024-01-01 Mon 10:30] =>  1:30'.
+    Every closed clock becomes a time range entry (or an open range, if the clock is still running) in the record at the clock’s start date.
+    The title of the heading becomes the summary text, and the org tags of the heading (including inherited ones) become tags.
+  - 'timewarrior': The interval format of Timewarrior’s data files (e.g. '~/.timewarrior/data/2024-01.data').
+    Every interval becomes a time range entry (or an open range, if the interval is open) in the record at the interval’s start date.
+    The interval tags become tags (invalid characters are replaced by '_'), and the annotation becomes the summary text.
+
+You can use the filter flags to only import a subset of the data, e.g. '--period 2024-05' or '--since 2024-05-13'.
+
+If there is no record at the respective date yet, it creates a new one.
+Otherwise, the imported entries are appended to the existing record, and the existing record’s summary and should-total remain untouched.
+For 'ics', events that already exist in the record (i.e., entries with the same time values and summary) are skipped, so you can safely re-import the same calendar.
+Dates and times are formatted in accordance with your preferences (or the prevalent style of the target file).
+`
+}
+
+func (opt *Import) Run(ctx app.Context) app.Error {
+	opt.NoStyleArgs.Apply(&ctx)
+	if opt.Format != "csv" && (len(opt.Columns) > 0 || opt.DateFormat != "") {
+		return app.NewErrorWithCode(app.LOGICAL_ERROR, "Incompatible flags", "The '--columns' and '--date-format' flags can only be used with '--format csv'", nil)
+	}
+	delimiter, dErr := opt.CsvDelimiter()
+	if dErr != nil && opt.Format == "csv" {
+		return dErr
+	}
+	input, err := ctx.ReadRawInput(opt.From)
+	if err != nil {
+		return err
+	}
+	var warnings []service.UsageWarning
+	records, cErr := func() ([]klog.Record, error) {
+		switch opt.Format {
+		case "csv":
+			return csv.FromCsv(input, csv.ReadOptions{Delimiter: delimiter, Columns: opt.Columns, DateFormat: opt.DateFormat})
+		case "ics":
+			rs, skipped, err := ical.FromIcal(input, ctx.Now().Location())
+			for _, s := range skipped {
+				warnings = append(warnings, service.NewSkippedImportWarning(s))
+			}
+			return rs, err
+		case "org":
+			return org.FromOrg(input)
+		case "timewarrior":
+			return timewarrior.FromTimewarrior(input, ctx.Now().Location())
+		default:
+			return json.FromJson(input)
+		}
+	}()
+	if cErr != nil {
+		return app.NewErrorWithCode(
+			app.GENERAL_ERROR,
+			"Invalid input data",
+			cErr.Error(),
+			cErr,
+		)
+	}
+	records, fErr := opt.ApplyFilter(ctx.Now(), ctx.Config().NamedFilters.UnwrapOr(nil), records)
+	if fErr != nil {
+		return fErr
+	}
+	return helper.Import(ctx, helper.ImportOpts{
+		ReconcileOpts:       helper.ReconcileOpts{OutputFileArgs: opt.OutputFileArgs, WarnArgs: opt.WarnArgs},
+		SkipExistingEntries: opt.Format == "ics",
+		Warnings:            warnings,
+	}, records)
+}
@@ -0,0 +1,321 @@
+package cli
+
+import (
+	"testing"
+
+	"github.com/jotaen/klog/klog"
+	"github.com/jotaen/klog/klog/app/cli/args"
+	"github.com/jotaen/klog/klog/service/period"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestImportJsonIntoEmptyFile(t *testing.T) {
+	state, err := NewTestingContext()._SetReloadAfterWrite()._SetRecords("")._SetRawInput(`{"records":[{
+		"date":"2000-01-01",
+		"summary":"Hello #world",
+		"should_total":"8h!",
+		"entries":[
+			{"type":"range","summary":"Foo","start":"8:00","end":"12:30"},
+			{"type":"duration","summary":"","total":"-30m"},
+			{"type":"open_range","summary":"Bar\nBaz #qux","start":"13:00"}
+		]
+	},{
+		"date":"2000-01-02",
+		"summary":"",
+		"should_total":"0m",
+		"entries":[{"type":"duration","summary":"","total":"1h"}]
+	}],"warnings":null,"errors":null}`)._Run((&Import{}).Run)
+	require.Nil(t, err)
+	assert.Equal(t, `2000-01-01 (8h!)
+Hello #world
+    8:00 - 12:30 Foo
+    -30m
+    13:00 - ? Bar
+        Baz #qux
+
+2000-01-02
+    1h
+`, state.writtenFileContents)
+}
+
+func TestImportJsonKeepsDuplicateEntries(t *testing.T) {
+	state, err := NewTestingContext()._SetReloadAfterWrite()._SetRecords(`
+2000-01-01
+    1h
+`)._SetRawInput(`{"records":[{
+		"date":"2000-01-01",
+		"entries":[{"type":"duration","summary":"","total":"1h"}]
+	}]}`)._Run((&Import{}).Run)
+	require.Nil(t, err)
+	assert.Equal(t, `
+2000-01-01
+    1h
+    1h
+`, state.writtenFileContents)
+}
+
+func TestImportJsonMergesIntoExistingRecords(t *testing.T) {
+	state, err := NewTestingContext()._SetReloadAfterWrite()._SetRecords(`
+1999-12-31
+    1h
+
+2000-01-02 (5h!)
+Existing
+    9:00am-10:00am
+`)._SetRawInput(`{"records":[{
+		"date":"2000-01-02",
+		"summary":"Ignored",
+		"should_total":"8h!",
+		"entries":[{"type":"range","summary":"","start":"13:00","end":"14:00"}]
+	},{
+		"date":"2000-01-01",
+		"entries":[{"type":"duration","summary":"Test","total":"2h"}]
+	}]}`)._Run((&Import{}).Run)
+	require.Nil(t, err)
+	assert.Equal(t, `
+1999-12-31
+    1h
+
+2000-01-01
+    2h Test
+
+2000-01-02 (5h!)
+Existing
+    9:00am-10:00am
+    1:00pm-2:00pm
+`, state.writtenFileContents)
+}
+
+func TestImportJsonWithStylePreferences(t *testing.T) {
+	state, err := NewTestingContext()._SetReloadAfterWrite()._SetRecords(`
+1999-12-31
+    1h
+`)._SetFileConfig(`
+date_format = YYYY/MM/DD
+time_convention = 12h
+`)._SetRawInput(`{"records":[{
+		"date":"2000-01-01",
+		"entries":[{"type":"range","start":"13:00","end":"14:00"}]
+	}]}`)._Run((&Import{}).Run)
+	require.Nil(t, err)
+	assert.Equal(t, `
+1999-12-31
+    1h
+
+2000/01/01
+    1:00pm - 2:00pm
+`, state.writtenFileContents)
+}
+
+func TestImportJsonFailsForInvalidInput(t *testing.T) {
+	state, err := NewTestingContext()._SetReloadAfterWrite()._SetRecords("")._SetRawInput(`{"records":[{"date":"2000-01-01","entries":[{"type":"range","start":"13:00"}]}]}`)._Run((&Import{}).Run)
+	require.Error(t, err)
+	assert.Equal(t, "Invalid input data", err.Error())
+	assert.Equal(t, "Record 1 (2000-01-01): Entry 1: Invalid start or end time", err.Details())
+	assert.Equal(t, "", state.writtenFileContents)
+}
+
+func TestImportJsonFailsIfRecordWouldBecomeInvalid(t *testing.T) {
+	_, err := NewTestingContext()._SetReloadAfterWrite()._SetRecords(`
+2000-01-01
+    8:00 - ?
+`)._SetRawInput(`{"records":[{"date":"2000-01-01","entries":[{"type":"open_range","start":"13:00"}]}]}`)._Run((&Import{}).Run)
+	require.Error(t, err)
+	assert.Equal(t, "Manipulation failed", err.Error())
+	assert.Equal(t, "2000-01-01: There is already an open range in this record", err.Details())
+}
+
+var icsInput = `BEGIN:VCALENDAR
+VERSION:2.0
+BEGIN:VEVENT
+DTSTART:20240131T140000
+DTEND:20240131T153000
+SUMMARY:Out of period
+END:VEVENT
+BEGIN:VEVENT
+DTSTART:20240201T090000
+DTEND:20240201T093000
+SUMMARY:Standup
+CATEGORIES:Meeting
+END:VEVENT
+BEGIN:VEVENT
+DTSTART:20240202T130000
+DTEND:20240202T140000
+SUMMARY:Planning
+END:VEVENT
+END:VCALENDAR
+`
+
+func TestImportIcalWithinPeriod(t *testing.T) {
+	state, err := NewTestingContext()._SetReloadAfterWrite()._SetRecords(`
+2024-02-01
+Existing record
+    8:00 - 9:00
+`)._SetRawInput(icsInput)._Run((&Import{
+		Format:     "ics",
+		FilterArgs: args.FilterArgs{Period: period.NewMonthFromDate(klog.Ɀ_Date_(2024, 2, 1)).Period()},
+	}).Run)
+	require.Nil(t, err)
+	assert.Equal(t, `
+2024-02-01
+Existing record
+    8:00 - 9:00
+    9:00 - 9:30 Standup #meeting
+
+2024-02-02
+    13:00 - 14:00 Planning
+`, state.writtenFileContents)
+}
+
+func TestImportIcalIsIdempotent(t *testing.T) {
+	ctx := NewTestingContext()._SetReloadAfterWrite()._SetRecords(`
+2024-02-01
+    9:00am - 9:30am Standup #meeting
+`)._SetRawInput(icsInput)
+	state, err := ctx._Run((&Import{
+		Format:     "ics",
+		FilterArgs: args.FilterArgs{Since: klog.Ɀ_Date_(2024, 2, 1)},
+	}).Run)
+	require.Nil(t, err)
+	assert.Equal(t, `
+2024-02-01
+    9:00am - 9:30am Standup #meeting
+
+2024-02-02
+    1:00pm - 2:00pm Planning
+`, state.writtenFileContents)
+
+	state, err = ctx._SetRecords(state.writtenFileContents)._Run((&Import{
+		Format:     "ics",
+		FilterArgs: args.FilterArgs{Since: klog.Ɀ_Date_(2024, 2, 1)},
+	}).Run)
+	require.Nil(t, err)
+	assert.Equal(t, "\nNothing to import, all entries exist already.\n", state.printBuffer)
+	assert.Equal(t, `
+2024-02-01
+    9:00am - 9:30am Standup #meeting
+
+2024-02-02
+    1:00pm - 2:00pm Planning
+`, state.writtenFileContents)
+}
+
+func TestImportIcalWarnsAboutSkippedEvents(t *testing.T) {
+	state, err := NewTestingContext()._SetReloadAfterWrite()._SetRecords("")._SetRawInput(`BEGIN:VCALENDAR
+BEGIN:VEVENT
+UID:abc-1
+DTSTART:20240201T090000
+DTEND:20240201T093000
+SUMMARY:Standup
+END:VEVENT
+BEGIN:VEVENT
+UID:abc-2
+DTSTART:20240201T100000
+DTEND:20240205T100000
+SUMMARY:Offsite
+END:VEVENT
+END:VCALENDAR
+`)._Run((&Import{Format: "ics"}).Run)
+	require.Nil(t, err)
+	assert.Equal(t, `2024-02-01
+    9:00 - 9:30 Standup
+`, state.writtenFileContents)
+	assert.Contains(t, state.printBuffer, "Event `Offsite` (UID abc-2): The time span must not exceed two days")
+}
+
+func TestImportTimewarrior(t *testing.T) {
+	state, err := NewTestingContext()._SetReloadAfterWrite()._SetRecords(`
+2024-02-01
+    8:00 - 9:00
+`)._SetNow(2024, 2, 2, 12, 0)._SetRawInput(`
+inc 20240201T090000Z - 20240201T093000Z # meeting # "Standup"
+inc 20240202T100000Z # coding
+`)._Run((&Import{Format: "timewarrior"}).Run)
+	require.Nil(t, err)
+	assert.Equal(t, `
+2024-02-01
+    8:00 - 9:00
+    9:00 - 9:30 Standup #meeting
+
+2024-02-02
+    10:00 - ? #coding
+`, state.writtenFileContents)
+}
+
+func TestImportCsvInConfiguredStyle(t *testing.T) {
+	state, err := NewTestingContext()._SetReloadAfterWrite()._SetRecords(`
+2024/01/01
+    1h
+`)._SetFileConfig(`
+date_format = YYYY-MM-DD
+time_convention = 12h
+`)._SetRawInput(`Day;From;To;Task;Project
+2024-01-02;09:00;10:30;Design review;Website
+2024-01-02;13:00;14:00;;
+`)._Run((&Import{
+		Format:  "csv",
+		CsvArgs: args.CsvArgs{Delimiter: ";"},
+		Columns: map[string]string{"date": "Day", "start": "From", "end": "To", "description": "Task"},
+	}).Run)
+	require.Nil(t, err)
+	assert.Equal(t, `
+2024/01/01
+    1h
+
+2024-01-02
+    9:00am - 10:30am Design review #website
+    1:00pm - 2:00pm
+`, state.writtenFileContents)
+}
+
+func TestImportCsvFailsForInvalidInput(t *testing.T) {
+	_, err := NewTestingContext()._SetReloadAfterWrite()._SetRecords("")._SetRawInput(`Date,Duration
+2024-01-02,1h
+2024-01-03,abc
+`)._Run((&Import{Format: "csv", CsvArgs: args.CsvArgs{Delimiter: ","}}).Run)
+	require.Error(t, err)
+	assert.Equal(t, "Invalid input data", err.Error())
+	assert.Equal(t, "Line 3: Invalid duration", err.Details())
+}
+
+func TestImportCsvWithDateFormat(t *testing.T) {
+	state, err := NewTestingContext()._SetReloadAfterWrite()._SetRecords("")._SetRawInput(`Start date,Start time,End time,Description
+01/31/2024,09:00:00,10:00:00,Planning
+`)._Run((&Import{Format: "csv", CsvArgs: args.CsvArgs{Delimiter: ","}, DateFormat: "MM/DD/YYYY"}).Run)
+	require.Nil(t, err)
+	assert.Equal(t, `2024-01-31
+    9:00 - 10:00 Planning
+`, state.writtenFileContents)
+}
+
+func TestImportRejectsCsvFlagsForOtherFormats(t *testing.T) {
+	for _, cmd := range []*Import{
+		{Format: "json", Columns: map[string]string{"date": "Day"}},
+		{Format: "ics", DateFormat: "DD.MM.YYYY"},
+	} {
+		_, err := NewTestingContext()._SetRecords("")._SetRawInput(`{"records":[]}`)._Run(cmd.Run)
+		require.Error(t, err)
+		assert.Equal(t, "Incompatible flags", err.Error())
+	}
+}
+
+func TestImportOrg(t *testing.T) {
+	state, err := NewTestingContext()._SetReloadAfterWrite()._SetRecords(`
+2024-02-01
+    8:00 - 9:00
+`)._SetNow(2024, 2, 2, 12, 0)._SetRawInput(`
+* Project :acme:
+** TODO Write spec
+   :LOGBOOK:
+   CLOCK: [2024-02-01 Thu 09:00]--[2024-02-01 Thu 10:30] =>  1:30
+   :END:
+`)._Run((&Import{Format: "org"}).Run)
+	require.Nil(t, err)
+	assert.Equal(t, `
+2024-02-01
+    8:00 - 9:00
+    9:00 - 10:30 Write spec #acme
+`, state.writtenFileContents)
+}
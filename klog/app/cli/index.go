@@ -23,11 +23,15 @@ type Cli struct {
 	Default Default `hidden:"" cmd:"" default:"withargs" help:""`
 
 	// Evaluate Files
-	Print  Print  `cmd:"" name:"print" group:"Evaluate Files" help:"Pretty-print records."`
-	Total  Total  `cmd:"" name:"total" group:"Evaluate Files" help:"Evaluate the total time."`
-	Report Report `cmd:"" name:"report" group:"Evaluate Files" help:"Print an aggregated calendar report."`
-	Tags   Tags   `cmd:"" name:"tags" group:"Evaluate Files" help:"Print total times aggregated by tags."`
-	Today  Today  `cmd:"" name:"today" group:"Evaluate Files" help:"Evaluate the current day."`
+	Print     Print     `cmd:"" name:"print" group:"Evaluate Files" help:"Pretty-print records."`
+	Total     Total     `cmd:"" name:"total" group:"Evaluate Files" help:"Evaluate the total time."`
+	Report    Report    `cmd:"" name:"report" group:"Evaluate Files" help:"Print an aggregated calendar report."`
+	Tags      Tags      `cmd:"" name:"tags" group:"Evaluate Files" help:"Print total times aggregated by tags."`
+	Today     Today     `cmd:"" name:"today" group:"Evaluate Files" help:"Evaluate the current day."`
+	Invoice   Invoice   `cmd:"" name:"invoice" group:"Evaluate Files" help:"Print an itemised invoice based on hourly rates per tag."`
+	Stats     Stats     `cmd:"" name:"stats" group:"Evaluate Files" help:"Print statistics about your work patterns."`
+	Heatmap   Heatmap   `cmd:"" name:"heatmap" group:"Evaluate Files" help:"Print a calendar heatmap of the daily totals."`
+	Histogram Histogram `cmd:"" name:"histogram" group:"Evaluate Files" help:"Print a histogram of the time by hour of day."`
 
 	// Manipulate Files
 	Track  Track  `cmd:"" name:"track" group:"Manipulate Files" help:"Add a new entry to a record."`
@@ -36,6 +40,7 @@ type Cli struct {
 	Pause  Pause  `cmd:"" name:"pause" group:"Manipulate Files" help:"Pause the open time range."`
 	Switch Switch `cmd:"" name:"switch" group:"Manipulate Files" help:"Close open range and starts a new one."`
 	Create Create `cmd:"" name:"create" group:"Manipulate Files" help:"Create a new, empty record."`
+	Import Import `cmd:"" name:"import" group:"Manipulate Files" help:"Import records from other data formats."`
 
 	// Manage Files
 	Bookmarks Bookmarks `cmd:"" name:"bookmarks" group:"Manage Files" aliases:"bk" help:"Named aliases for often-used files."`
@@ -48,6 +53,7 @@ type Cli struct {
 	Config     Config        `cmd:"" name:"config" group:"Misc" help:"Print the current configuration."`
 	Info       Info          `cmd:"" name:"info" group:"Misc" help:"Print information about klog."`
 	Json       Json          `cmd:"" name:"json" group:"Misc" help:"Convert records to JSON."`
+	Export     Export        `cmd:"" name:"export" group:"Misc" help:"Convert records to other data formats."`
 	Completion kc.Completion `cmd:"" name:"completion" group:"Misc" help:"Output shell code for enabling tab completion."`
 }
 
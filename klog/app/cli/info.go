@@ -1,18 +1,31 @@
 package cli
 
 import (
+	"strconv"
+	"strings"
+
+	"github.com/jotaen/klog/klog"
 	"github.com/jotaen/klog/klog/app"
+	"github.com/jotaen/klog/klog/app/cli/args"
 	"github.com/jotaen/klog/klog/app/cli/prettify"
+	"github.com/jotaen/klog/klog/parser"
+	"github.com/jotaen/klog/klog/service/filter"
+	tf "github.com/jotaen/klog/lib/terminalformat"
 )
 
 type Info struct {
-	Spec      bool `name:"spec" help:"Print the .klg file format specification."`
-	License   bool `name:"license" help:"Print license / copyright information."`
-	About     bool `name:"about" help:"Print meta information about klog."`
-	Filtering bool `name:"filtering" help:"Print documentation for using filter expressions."`
+	Spec          bool   `name:"spec" help:"Print the .klg file format specification."`
+	License       bool   `name:"license" help:"Print license / copyright information."`
+	About         bool   `name:"about" help:"Print meta information about klog."`
+	Filtering     bool   `name:"filtering" help:"Print documentation for using filter expressions."`
+	ExplainFilter string `name:"explain-filter" placeholder:"EXPR" help:"Explain how a filter expression is evaluated: print the parsed expression, and whether the records and entries of the input files match it." completion-predictor:"named_filter"`
+	args.InputFilesArgs
 }
 
 func (opt *Info) Run(ctx app.Context) app.Error {
+	if opt.ExplainFilter != "" {
+		return opt.explainFilter(ctx)
+	}
 	text, err := func() (string, app.Error) {
 		if opt.Spec {
 			return ctx.Meta().Specification + "\n", nil
@@ -29,6 +42,10 @@ This would evaluate all records and entries in April 2025 that match the tag #wo
 
 Filters can match at record-level and/or at entry-level. It only keeps the data that satisfies the filter condition. For entry-level filters, this means that all non-matching entries are stripped from the record.
 
+If a filter doesn’t yield the expected result, you can check how it’s evaluated for each record and entry, e.g.:
+
+    klog info --explain-filter='2025-04 && #work' mytimes.klg
+
 Examples:
     2025-04-20 || 2020-04-21
         All entries at either 2025-04-20 or 2020-04-21.
@@ -68,14 +85,70 @@ Operands:
             2025-04-30...2025-05-14
             2025-06-19...
             ...2025-08-30
+    today
+    yesterday
+    -Nd
+    -Nw
+        Relative dates, which are resolved in relation to the current date: today, yesterday, N days ago or N weeks ago.
+        They can be used instead of YYYY-MM-DD, also in date ranges.
+        Examples:
+            -14d...
+            yesterday...today
+            -2w...-1w
+    weekday:xxx
+    weekday:xxx...xxx
+        Entries at that weekday, or within that range of weekdays (inclusive), where xxx is the name of the weekday, e.g. 'monday' or 'mon'.
+        Ranges can wrap around the end of the week, e.g. 'fri...mon'.
+        Examples: weekday:sat || weekday:sun || weekday:mon...fri
     #tag
     #tag=value
         Entries matching that a tag.
         Examples: #work || #project=467 || #project='#312'
+        You can use '*' as wildcard in the tag name or value, which matches any sequence of characters.
+        Examples: #client-* || #ticket=PROJ-*
+        For hierarchical tags (e.g. #acme/backend), the parent tags match as well.
+        Example: #acme matches #acme/backend and #acme/backend/api
     type:xxx
         Entries of that type, where xxx can be either:
         range, open-range, duration, duration-positive, duration-negative
         Example: type:duration
+    duration<DURATION
+    duration<=DURATION
+    duration>DURATION
+    duration>=DURATION
+    duration=DURATION
+        Entries whose duration is less than, less than or equal to, greater than, greater than or equal to, or equal to that duration. You can use '0' as shorthand for '0m'. Open ranges never match.
+        Examples: (duration>4h && type:range) || duration<=15m || duration=-30m
+    start<TIME    end<TIME
+    start<=TIME   end<=TIME
+    start>TIME    end>TIME
+    start>=TIME   end>=TIME
+    start=TIME    end=TIME
+        Entries whose start / end time compares to that time (see 'duration' above). Only ranges have start and end times; open ranges only have a start time.
+        Shifted times are taken into account, so '<23:00' is earlier than '0:00', and '1:00>' is later than '23:59'.
+        Examples: start>=18:00 || end<=7:00 || end>23:59
+    has:should
+        Records that have a should-total. It matches all entries of the record.
+        Example: has:should && 2025-04
+    diff<DURATION
+    diff<=DURATION
+    diff>DURATION
+    diff>=DURATION
+    diff=DURATION
+        Records whose difference between the actual total and the should-total compares to that duration (see 'duration' above).
+        It matches all entries of the record. The difference is always evaluated for the entire record, even when other operands only match some of its entries.
+        E.g., '#work && diff>0' yields the #work entries of all records with overtime, regardless of whether the overtime stems from #work entries or not.
+        Records without should-total never match (so they do match when negating the operand, e.g. '!(diff<0)').
+        Examples: diff<0 || diff>=+2h
+    summary~text
+    summary~"text"
+    summary~/regex/
+        Entries whose summary contains that text (regardless of upper or lower case), or whose summary matches that regular expression. Line breaks in the summary are treated as spaces.
+        Examples: summary~review || summary~"code review" || summary~/^(Fix|Hotfix) PROJ-\d+/
+    @name
+        Entries matching the named filter with that name, which you can define via the 'named_filters' setting in the config file.
+        Example config: named_filters = billable: #client-* && !#internal; weekend: weekday:sat...sun
+        Example: @billable && !@weekend
 `, nil
 		}
 
@@ -94,3 +167,103 @@ Operands:
 	ctx.Print(prettify.Reflower.Reflow(text, ""))
 	return nil
 }
+
+// explainFilter prints the predicate tree of the filter expression, and then
+// the evaluation result for every record and entry. For the entries, it shows
+// the result of every operand, e.g. `✓(✓#foo || ✗#bar) && ✗2025-04-01`.
+func (opt *Info) explainFilter(ctx app.Context) app.Error {
+	namedFilters := ctx.Config().NamedFilters.UnwrapOr(nil)
+	p, pErr := filter.ParseWithNamedFilters(opt.ExplainFilter, klog.NewDateFromGo(ctx.Now()), namedFilters)
+	if pErr != nil {
+		return app.NewErrorWithCode(
+			app.GENERAL_ERROR,
+			"Malformed filter query",
+			pErr.Error(),
+			pErr,
+		)
+	}
+	records, err := ctx.ReadInputs(opt.File...)
+	if err != nil {
+		return err
+	}
+	styler, serialiser := ctx.Serialise()
+	mark := func(isMatch bool) string {
+		if isMatch {
+			return styler.Props(tf.StyleProps{Color: tf.GREEN}).Format("✓")
+		}
+		return styler.Props(tf.StyleProps{Color: tf.RED}).Format("✗")
+	}
+	var render func(filter.Explanation) string
+	// renderOperands renders the operands of `&&` and `||`.
+	renderOperands := func(x filter.Explanation) string {
+		var operands []string
+		for _, o := range x.Operands {
+			operands = append(operands, render(o))
+		}
+		return strings.Join(operands, " "+filter.Describe(x.Predicate)+" ")
+	}
+	render = func(x filter.Explanation) string {
+		if x.Operands == nil {
+			return mark(x.IsMatch) + filter.Describe(x.Predicate)
+		}
+		if _, isNot := x.Predicate.(filter.Not); isNot {
+			return mark(x.IsMatch) + "!(" + renderOperands(x.Operands[0]) + ")"
+		}
+		return mark(x.IsMatch) + "(" + renderOperands(x) + ")"
+	}
+	// At the top level, the overall result is already shown on its own.
+	renderTopLevel := func(x filter.Explanation) string {
+		switch x.Predicate.(type) {
+		case filter.And, filter.Or:
+			return renderOperands(x)
+		}
+		return render(x)
+	}
+
+	ctx.Print("Filter expression:\n")
+	var printTree func(filter.Predicate, string)
+	printTree = func(p filter.Predicate, indent string) {
+		ctx.Print(indent + filter.Describe(p) + "\n")
+		for _, o := range filter.Operands(p) {
+			printTree(o, indent+"    ")
+		}
+	}
+	printTree(p, "    ")
+
+	subdued := styler.Props(tf.StyleProps{Color: tf.TEXT_SUBDUED})
+	for _, r := range records {
+		ctx.Print("\n")
+		if len(r.Entries()) == 0 {
+			x := filter.Explain(p, r, nil)
+			ctx.Print(mark(x.IsMatch) + " " + serialiser.Date(r.Date()) + " " + subdued.Format("(no entries)") + "\n")
+			ctx.Print("    " + renderTopLevel(x) + "\n")
+			continue
+		}
+		var xs []filter.Explanation
+		matchCount := 0
+		for i := range r.Entries() {
+			x := filter.Explain(p, r, &r.Entries()[i])
+			xs = append(xs, x)
+			if x.IsMatch {
+				matchCount++
+			}
+		}
+		ctx.Print(mark(matchCount > 0) + " " + serialiser.Date(r.Date()) + " " + subdued.Format(
+			"("+strconv.Itoa(matchCount)+" of "+strconv.Itoa(len(r.Entries()))+" entries)",
+		) + "\n")
+		for i, e := range r.Entries() {
+			value := klog.Unbox[string](&e,
+				func(tr klog.Range) string { return serialiser.Range(tr) },
+				func(d klog.Duration) string { return serialiser.Duration(d) },
+				func(o klog.OpenRange) string { return serialiser.OpenRange(o) },
+			)
+			summary := parser.SummaryText(e.Summary())
+			if len(summary) > 0 && summary[0] != "" {
+				value += " " + serialiser.Summary(summary[:1])
+			}
+			ctx.Print("    " + mark(xs[i].IsMatch) + " " + value + "\n")
+			ctx.Print("        " + renderTopLevel(xs[i]) + "\n")
+		}
+	}
+	return nil
+}
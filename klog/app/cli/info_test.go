@@ -0,0 +1,83 @@
+package cli
+
+import (
+	"testing"
+
+	"github.com/jotaen/klog/klog/service/filter"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExplainFilter(t *testing.T) {
+	state, err := NewTestingContext()._SetRecords(`
+2024-01-05
+Work #acme
+	9:00-10:00 Fixed bug
+	2h #beta review
+	30m #internal
+
+2024-01-06
+#acme
+
+2024-04-01
+	1h #acme
+`)._SetNow(2024, 4, 2, 12, 0)._Run((&Info{ExplainFilter: "(#acme || #beta) && !(#internal || 2024-04) && ...today"}).Run)
+	require.Nil(t, err)
+	assert.Equal(t, `
+Filter expression:
+    &&
+        ||
+            #acme
+            #beta
+        !
+            ||
+                #internal
+                2024-04-01...2024-04-30
+        ...2024-04-02
+
+✓ 2024-01-05 (2 of 3 entries)
+    ✓ 9:00-10:00 Fixed bug
+        ✓(✓#acme || ✗#beta) && ✓!(✗#internal || ✗2024-04-01...2024-04-30) && ✓...2024-04-02
+    ✓ 2h #beta review
+        ✓(✓#acme || ✓#beta) && ✓!(✗#internal || ✗2024-04-01...2024-04-30) && ✓...2024-04-02
+    ✗ 30m #internal
+        ✓(✓#acme || ✗#beta) && ✗!(✓#internal || ✗2024-04-01...2024-04-30) && ✓...2024-04-02
+
+✓ 2024-01-06 (no entries)
+    ✓(✓#acme || ✗#beta) && ✓!(✗#internal || ✗2024-04-01...2024-04-30) && ✓...2024-04-02
+
+✗ 2024-04-01 (0 of 1 entries)
+    ✗ 1h #acme
+        ✓(✓#acme || ✗#beta) && ✗!(✗#internal || ✓2024-04-01...2024-04-30) && ✓...2024-04-02
+`, state.printBuffer)
+}
+
+func TestExplainFilterWithSingleOperand(t *testing.T) {
+	state, err := NewTestingContext()._SetRecords(`
+2024-01-05
+	1h #acme
+	2h
+`)._Run((&Info{ExplainFilter: "#acme"}).Run)
+	require.Nil(t, err)
+	assert.Equal(t, `
+Filter expression:
+    #acme
+
+✓ 2024-01-05 (1 of 2 entries)
+    ✓ 1h #acme
+        ✓#acme
+    ✗ 2h
+        ✗#acme
+`, state.printBuffer)
+}
+
+func TestExplainFilterFailsForMalformedQuery(t *testing.T) {
+	_, err := NewTestingContext()._SetRecords(`
+2024-01-05
+	1h #acme
+`)._Run((&Info{ExplainFilter: "#acme && foo"}).Run)
+	require.Error(t, err)
+	var pErr filter.ParseError
+	require.ErrorAs(t, err.Original(), &pErr)
+	assert.ErrorIs(t, pErr.Original(), filter.ErrUnrecognisedToken)
+}
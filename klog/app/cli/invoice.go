@@ -0,0 +1,260 @@
+package cli
+
+import (
+	"html"
+	"strings"
+
+	"github.com/jotaen/klog/klog"
+	"github.com/jotaen/klog/klog/app"
+	"github.com/jotaen/klog/klog/app/cli/args"
+	"github.com/jotaen/klog/klog/service"
+	tf "github.com/jotaen/klog/lib/terminalformat"
+)
+
+type Invoice struct {
+	Rates    string           `name:"rates" placeholder:"FILE" type:"path" help:"Read the hourly rates from this file (one rate per line). If absent, the rates are taken from the config file."`
+	Round    service.Rounding `name:"round" placeholder:"ROUNDING" short:"r" help:"Round up the time of every entry to a multiple of this value before billing it. ROUNDING can be one of '5m', '10m', '12m', '15m', '20m', '30m' or '60m' / '1h'."`
+	Format   string           `name:"format" placeholder:"FORMAT" help:"The format of the invoice. FORMAT can be 'text' (default), 'markdown' or 'html'." enum:"text,markdown,html," default:"text"`
+	Currency string           `name:"currency" placeholder:"CURRENCY" help:"The currency of the rates, e.g. 'EUR'. It’s only used for display purposes."`
+	args.FilterArgs
+	args.NowArgs
+	args.DecimalArgs
+	args.WarnArgs
+	args.NoStyleArgs
+	args.InputFilesArgs
+}
+
+func (opt *Invoice) Help() string {
+	return `
+Bills the time of the entries according to hourly rates per tag, and prints an itemised invoice.
+For every rate, there is a line item per date, along with the subtotal. At the end, there is the grand total of all rates.
+
+The rates are either specified in a file (via '--rates'), or in the config file (via the 'invoice_rates' setting).
+Every rate consists of a tag and the amount per hour, e.g. '#acme 120' or '#project=beta 95.50'.
+In the rates file, you put every rate on a separate line, whereas in the config file you separate them by commas.
+A rate for a tag without value (e.g. '#project') applies to all entries with that tag, regardless of their tag value.
+
+An entry must not match more than one rate, as that would bill it twice.
+Entries that don’t match any rate are not billed; their total time is stated at the end of the invoice.
+
+Use the filter flags to restrict the invoice to a certain period, e.g. '--period 2024-05' or '--last-month'.
+`
+}
+
+func (opt *Invoice) Run(ctx app.Context) app.Error {
+	if opt.Format != "text" {
+		(&args.NoStyleArgs{NoStyle: true}).Apply(&ctx)
+	}
+	opt.DecimalArgs.Apply(&ctx)
+	opt.NoStyleArgs.Apply(&ctx)
+	rates, rErr := opt.rates(ctx)
+	if rErr != nil {
+		return rErr
+	}
+	records, err := ctx.ReadInputs(opt.File...)
+	if err != nil {
+		return err
+	}
+	now := ctx.Now()
+	records, fErr := opt.ApplyFilter(now, ctx.Config().NamedFilters.UnwrapOr(nil), records)
+	if fErr != nil {
+		return fErr
+	}
+	nErr := opt.ApplyNow(now, records...)
+	if nErr != nil {
+		return nErr
+	}
+	invoice, iErr := service.NewInvoice(records, rates, opt.Round)
+	if iErr != nil {
+		return app.NewErrorWithCode(
+			app.LOGICAL_ERROR,
+			"Ambiguous rates",
+			iErr.Error(),
+			iErr,
+		)
+	}
+	switch opt.Format {
+	case "markdown":
+		opt.printMarkdown(ctx, records, invoice)
+	case "html":
+		opt.printHtml(ctx, records, invoice)
+	default:
+		opt.printText(ctx, invoice)
+	}
+	opt.WarnArgs.PrintWarningsToStderr(ctx, records, []service.UsageWarning{opt.NowArgs.GetWarning()})
+	return nil
+}
+
+func (opt *Invoice) rates(ctx app.Context) ([]service.Rate, app.Error) {
+	var rates []service.Rate
+	if opt.Rates != "" {
+		text, err := ctx.ReadRawInput(opt.Rates)
+		if err != nil {
+			return nil, err
+		}
+		rs, rErr := service.NewRatesFromString(text)
+		if rErr != nil {
+			return nil, app.NewErrorWithCode(
+				app.GENERAL_ERROR,
+				"Invalid rates file",
+				rErr.Error(),
+				rErr,
+			)
+		}
+		rates = rs
+	} else {
+		ctx.Config().InvoiceRates.Unwrap(func(rs []service.Rate) {
+			rates = rs
+		})
+	}
+	if len(rates) == 0 {
+		return nil, app.NewErrorWithCode(
+			app.GENERAL_ERROR,
+			"No rates specified",
+			"Please specify the hourly rates via the '--rates' flag, or via the 'invoice_rates' setting in the config file",
+			nil,
+		)
+	}
+	return rates, nil
+}
+
+func (opt *Invoice) amountHeader() string {
+	if opt.Currency == "" {
+		return "Amount"
+	}
+	return "Amount (" + opt.Currency + ")"
+}
+
+func (opt *Invoice) printText(ctx app.Context, invoice service.Invoice) {
+	styler, serialiser := ctx.Serialise()
+	table := tf.NewTable(4, " ")
+	table.Skip(1).CellR("Time").CellR("Rate").CellR(opt.amountHeader())
+	for _, section := range invoice.Sections {
+		if len(section.Items) == 0 {
+			continue
+		}
+		table.CellL(styler.Props(tf.StyleProps{Color: tf.TEXT_SUBDUED}).Format(section.Rate.Tag.ToString())).Skip(3)
+		for _, item := range section.Items {
+			table.CellL(item.Date.ToString()).
+				CellR(serialiser.Duration(item.Total)).
+				CellR(section.Rate.PerHour.ToString()).
+				CellR(item.Amount.ToString())
+		}
+		table.CellL("Subtotal").
+			CellR(serialiser.Duration(section.Total)).
+			Skip(1).
+			CellR(section.Amount.ToString())
+	}
+	table.Skip(1).Fill("=").Skip(1).Fill("=")
+	table.CellL("Total").
+		CellR(serialiser.Duration(invoice.Total)).
+		Skip(1).
+		CellR(styler.Props(tf.StyleProps{IsBold: true}).Format(invoice.Amount.ToString()))
+	table.Collect(ctx.Print)
+	if invoice.Unbilled.InMinutes() != 0 {
+		ctx.Print("(Not billed: " + serialiser.Duration(invoice.Unbilled) + ")\n")
+	}
+}
+
+func (opt *Invoice) printMarkdown(ctx app.Context, records []klog.Record, invoice service.Invoice) {
+	_, serialiser := ctx.Serialise()
+	ctx.Print("# Invoice\n")
+	if len(records) > 0 {
+		ctx.Print("\nPeriod: " + invoicePeriod(records) + "\n")
+	}
+	for _, section := range invoice.Sections {
+		if len(section.Items) == 0 {
+			continue
+		}
+		ctx.Print("\n## " + section.Rate.Tag.ToString() + "\n\n")
+		ctx.Print("| Date | Time | Rate | " + markdownCell(opt.amountHeader()) + " |\n")
+		ctx.Print("|:-----|-----:|-----:|-----:|\n")
+		for _, item := range section.Items {
+			ctx.Print("| " + strings.Join([]string{
+				item.Date.ToString(),
+				serialiser.Duration(item.Total),
+				section.Rate.PerHour.ToString(),
+				item.Amount.ToString(),
+			}, " | ") + " |\n")
+		}
+		ctx.Print("| **Subtotal** | **" + serialiser.Duration(section.Total) + "** | | **" + section.Amount.ToString() + "** |\n")
+	}
+	ctx.Print("\n## Total\n\n")
+	ctx.Print("| | Time | " + markdownCell(opt.amountHeader()) + " |\n")
+	ctx.Print("|:-----|-----:|-----:|\n")
+	for _, section := range invoice.Sections {
+		if len(section.Items) == 0 {
+			continue
+		}
+		ctx.Print("| " + markdownCell(section.Rate.Tag.ToString()) + " | " + serialiser.Duration(section.Total) + " | " + section.Amount.ToString() + " |\n")
+	}
+	ctx.Print("| **Total** | **" + serialiser.Duration(invoice.Total) + "** | **" + invoice.Amount.ToString() + "** |\n")
+	if invoice.Unbilled.InMinutes() != 0 {
+		ctx.Print("\nNot billed: " + serialiser.Duration(invoice.Unbilled) + "\n")
+	}
+}
+
+// markdownCell escapes the text, so that it can be used as table cell.
+func markdownCell(text string) string {
+	return strings.ReplaceAll(text, "|", "\\|")
+}
+
+func (opt *Invoice) printHtml(ctx app.Context, records []klog.Record, invoice service.Invoice) {
+	_, serialiser := ctx.Serialise()
+	row := func(cellTag string, values ...string) string {
+		result := "<tr>"
+		for i, v := range values {
+			align := ` style="text-align: right"`
+			if i == 0 {
+				align = ""
+			}
+			result += "<" + cellTag + align + ">" + html.EscapeString(v) + "</" + cellTag + ">"
+		}
+		return result + "</tr>\n"
+	}
+	ctx.Print("<!DOCTYPE html>\n<html>\n<head>\n<meta charset=\"utf-8\">\n<title>Invoice</title>\n</head>\n<body>\n")
+	ctx.Print("<h1>Invoice</h1>\n")
+	if len(records) > 0 {
+		ctx.Print("<p>Period: " + html.EscapeString(invoicePeriod(records)) + "</p>\n")
+	}
+	for _, section := range invoice.Sections {
+		if len(section.Items) == 0 {
+			continue
+		}
+		ctx.Print("<h2>" + html.EscapeString(section.Rate.Tag.ToString()) + "</h2>\n<table>\n")
+		ctx.Print(row("th", "Date", "Time", "Rate", opt.amountHeader()))
+		for _, item := range section.Items {
+			ctx.Print(row("td", item.Date.ToString(), serialiser.Duration(item.Total), section.Rate.PerHour.ToString(), item.Amount.ToString()))
+		}
+		ctx.Print(row("th", "Subtotal", serialiser.Duration(section.Total), "", section.Amount.ToString()))
+		ctx.Print("</table>\n")
+	}
+	ctx.Print("<h2>Total</h2>\n<table>\n")
+	ctx.Print(row("th", "", "Time", opt.amountHeader()))
+	for _, section := range invoice.Sections {
+		if len(section.Items) == 0 {
+			continue
+		}
+		ctx.Print(row("td", section.Rate.Tag.ToString(), serialiser.Duration(section.Total), section.Amount.ToString()))
+	}
+	ctx.Print(row("th", "Total", serialiser.Duration(invoice.Total), invoice.Amount.ToString()))
+	ctx.Print("</table>\n")
+	if invoice.Unbilled.InMinutes() != 0 {
+		ctx.Print("<p>Not billed: " + html.EscapeString(serialiser.Duration(invoice.Unbilled)) + "</p>\n")
+	}
+	ctx.Print("</body>\n</html>\n")
+}
+
+// invoicePeriod returns the date range of the records, e.g. `2024-05-01 – 2024-05-31`.
+func invoicePeriod(records []klog.Record) string {
+	first, last := records[0].Date(), records[0].Date()
+	for _, r := range records {
+		if !r.Date().IsAfterOrEqual(first) {
+			first = r.Date()
+		}
+		if r.Date().IsAfterOrEqual(last) {
+			last = r.Date()
+		}
+	}
+	return first.ToString() + " – " + last.ToString()
+}
@@ -0,0 +1,205 @@
+package cli
+
+import (
+	"testing"
+
+	"github.com/jotaen/klog/klog/app"
+	"github.com/jotaen/klog/klog/app/cli/args"
+	"github.com/jotaen/klog/klog/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const invoiceRecords = `
+2024-05-02
+    1h10m Planning #acme
+    30m Call #beta=x
+
+2024-05-01
+    9:00 - 10:20 #acme
+    45m Untagged
+    2h #beta=y
+`
+
+func TestPrintInvoiceWithRatesFromConfig(t *testing.T) {
+	state, err := NewTestingContext()._SetRecords(invoiceRecords)._SetFileConfig(`
+invoice_rates = #acme 120, #beta=x 90
+`)._Run((&Invoice{}).Run)
+	require.Nil(t, err)
+	assert.Equal(t, `
+            Time   Rate Amount
+#acme                         
+2024-05-01 1h20m 120.00 160.00
+2024-05-02 1h10m 120.00 140.00
+Subtotal   2h30m        300.00
+#beta=x                       
+2024-05-02   30m  90.00  45.00
+Subtotal     30m         45.00
+           =====        ======
+Total         3h        345.00
+(Not billed: 2h45m)
+`, state.printBuffer)
+}
+
+func TestPrintInvoiceWithRatesFileAndRounding(t *testing.T) {
+	state, err := NewTestingContext()._SetRecords(invoiceRecords)._SetRawInput(`
+#acme 100
+#beta 80.50
+`)._Run((&Invoice{
+		Rates:    "rates.txt",
+		Round:    func() service.Rounding { r, _ := service.NewRounding(60); return r }(),
+		Currency: "EUR",
+	}).Run)
+	require.Nil(t, err)
+	assert.Equal(t, `
+           Time   Rate Amount (EUR)
+#acme                              
+2024-05-01   2h 100.00       200.00
+2024-05-02   2h 100.00       200.00
+Subtotal     4h              400.00
+#beta                              
+2024-05-01   2h  80.50       161.00
+2024-05-02   1h  80.50        80.50
+Subtotal     3h              241.50
+           ====        ============
+Total        7h              641.50
+(Not billed: 1h)
+`, state.printBuffer)
+}
+
+func TestPrintInvoiceAsMarkdown(t *testing.T) {
+	state, err := NewTestingContext()._SetRecords(invoiceRecords)._SetFileConfig(`
+invoice_rates = #acme 120, #beta=x 90
+`)._Run((&Invoice{
+		Format:      "markdown",
+		DecimalArgs: args.DecimalArgs{Decimal: true},
+	}).Run)
+	require.Nil(t, err)
+	assert.Equal(t, `
+# Invoice
+
+Period: 2024-05-01 – 2024-05-02
+
+## #acme
+
+| Date | Time | Rate | Amount |
+|:-----|-----:|-----:|-----:|
+| 2024-05-01 | 80 | 120.00 | 160.00 |
+| 2024-05-02 | 70 | 120.00 | 140.00 |
+| **Subtotal** | **150** | | **300.00** |
+
+## #beta=x
+
+| Date | Time | Rate | Amount |
+|:-----|-----:|-----:|-----:|
+| 2024-05-02 | 30 | 90.00 | 45.00 |
+| **Subtotal** | **30** | | **45.00** |
+
+## Total
+
+| | Time | Amount |
+|:-----|-----:|-----:|
+| #acme | 150 | 300.00 |
+| #beta=x | 30 | 45.00 |
+| **Total** | **180** | **345.00** |
+
+Not billed: 165
+`, state.printBuffer)
+}
+
+func TestPrintInvoiceAsHtml(t *testing.T) {
+	state, err := NewTestingContext()._SetRecords(`
+2024-05-01
+    1h #client="A&B"
+`)._SetFileConfig(`
+invoice_rates = #client="A&B" 50
+`)._Run((&Invoice{Format: "html"}).Run)
+	require.Nil(t, err)
+	assert.Equal(t, `
+<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Invoice</title>
+</head>
+<body>
+<h1>Invoice</h1>
+<p>Period: 2024-05-01 – 2024-05-01</p>
+<h2>#client=&#34;A&amp;B&#34;</h2>
+<table>
+<tr><th>Date</th><th style="text-align: right">Time</th><th style="text-align: right">Rate</th><th style="text-align: right">Amount</th></tr>
+<tr><td>2024-05-01</td><td style="text-align: right">1h</td><td style="text-align: right">50.00</td><td style="text-align: right">50.00</td></tr>
+<tr><th>Subtotal</th><th style="text-align: right">1h</th><th style="text-align: right"></th><th style="text-align: right">50.00</th></tr>
+</table>
+<h2>Total</h2>
+<table>
+<tr><th></th><th style="text-align: right">Time</th><th style="text-align: right">Amount</th></tr>
+<tr><td>#client=&#34;A&amp;B&#34;</td><td style="text-align: right">1h</td><td style="text-align: right">50.00</td></tr>
+<tr><th>Total</th><th style="text-align: right">1h</th><th style="text-align: right">50.00</th></tr>
+</table>
+</body>
+</html>
+`, state.printBuffer)
+}
+
+func TestPrintInvoiceFailsWithoutRates(t *testing.T) {
+	_, err := NewTestingContext()._SetRecords(invoiceRecords)._Run((&Invoice{}).Run)
+	require.Error(t, err)
+	assert.Equal(t, "No rates specified", err.Error())
+}
+
+func TestPrintInvoiceFailsForAmbiguousRates(t *testing.T) {
+	_, err := NewTestingContext()._SetRecords(invoiceRecords)._SetFileConfig(`
+invoice_rates = #acme 120, #beta 90, #beta=x 100
+`)._Run((&Invoice{}).Run)
+	require.Error(t, err)
+	assert.Equal(t, app.LOGICAL_ERROR, err.Code())
+	assert.Equal(t, "The entry `30m` at 2024-05-02 matches more than one rate", err.Details())
+}
+
+func TestPrintInvoiceAsMarkdownEscapesTableCells(t *testing.T) {
+	state, err := NewTestingContext()._SetRecords(`
+2024-05-01
+    1h #client="A|B"
+`)._SetRawInput(`#client="A|B" 100`)._Run((&Invoice{
+		Format:   "markdown",
+		Rates:    "rates.txt",
+		Currency: "EUR|USD",
+	}).Run)
+	require.Nil(t, err)
+	assert.Equal(t, `
+# Invoice
+
+Period: 2024-05-01 – 2024-05-01
+
+## #client="A|B"
+
+| Date | Time | Rate | Amount (EUR\|USD) |
+|:-----|-----:|-----:|-----:|
+| 2024-05-01 | 1h | 100.00 | 100.00 |
+| **Subtotal** | **1h** | | **100.00** |
+
+## Total
+
+| | Time | Amount (EUR\|USD) |
+|:-----|-----:|-----:|
+| #client="A\|B" | 1h | 100.00 |
+| **Total** | **1h** | **100.00** |
+`, state.printBuffer)
+}
+
+func TestPrintInvoiceWarningsToStderr(t *testing.T) {
+	for _, format := range []string{"text", "markdown", "html"} {
+		state, err := NewTestingContext()._SetNow(2024, 5, 3, 12, 0)._SetRecords(`
+2024-05-01
+    1h #acme
+    9:00 - ?
+`)._SetRawInput(`#acme 100`)._Run((&Invoice{
+			Format: format,
+			Rates:  "rates.txt",
+		}).Run)
+		require.Nil(t, err)
+		assert.NotContains(t, state.printBuffer, "[WARNING]", format)
+		assert.Contains(t, state.printErrBuffer, "[WARNING]", format)
+	}
+}
@@ -46,7 +46,7 @@ func (opt *Json) Run(ctx app.Context) app.Error {
 	if nErr != nil {
 		return nErr
 	}
-	records, fErr := opt.ApplyFilter(now, records)
+	records, fErr := opt.ApplyFilter(now, ctx.Config().NamedFilters.UnwrapOr(nil), records)
 	if fErr != nil {
 		return fErr
 	}
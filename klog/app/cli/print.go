@@ -37,7 +37,7 @@ func (opt *Print) Run(ctx app.Context) app.Error {
 		return err
 	}
 	now := ctx.Now()
-	records, fErr := opt.ApplyFilter(now, records)
+	records, fErr := opt.ApplyFilter(now, ctx.Config().NamedFilters.UnwrapOr(nil), records)
 	if fErr != nil {
 		return fErr
 	}
@@ -7,6 +7,7 @@ import (
 	"github.com/jotaen/klog/klog"
 	"github.com/jotaen/klog/klog/app"
 	"github.com/jotaen/klog/klog/app/cli/args"
+	"github.com/jotaen/klog/klog/app/cli/helper"
 	"github.com/jotaen/klog/klog/app/cli/report"
 	"github.com/jotaen/klog/klog/service"
 	"github.com/jotaen/klog/klog/service/period"
@@ -18,15 +19,53 @@ type Report struct {
 	Fill            bool   `name:"fill" short:"f" help:"Fill any calendar gaps and show a consecutive sequence of dates."`
 	Chart           bool   `name:"chart" short:"c" help:"Includes a bar chart rendering, to aid visual comparison."`
 	ChartResolution int    `name:"chart-res" help:"Configure the chart resolution. INT must be a positive integer, denoting the minutes per rendered block."`
+	Balance         bool   `name:"balance" short:"b" help:"Show the running balance, i.e. the accumulated difference between actual and should-total time. It starts from the 'opening_balance' setting in the config file."`
+	Pivot           bool   `name:"pivot" help:"Break down the totals by tag, with one column per tag."`
+	PivotTag        string `name:"pivot-tag" placeholder:"TAG" help:"Break down the totals by the values of this tag, with one column per tag value. E.g., '--pivot-tag project' yields columns such as '#project=alpha' and '#project=beta'. (Implies '--pivot'.)"`
 	args.DiffArgs
 	args.FilterArgs
 	args.NowArgs
 	args.DecimalArgs
 	args.WarnArgs
 	args.NoStyleArgs
+	args.OutputArgs
 	args.InputFilesArgs
 }
 
+type reportJsonView struct {
+	Aggregation string                 `json:"aggregation"`
+	Periods     []reportPeriodJsonView `json:"periods"`
+	helper.TotalsView
+	*reportOpeningBalanceJsonView
+	*reportBalanceJsonView
+	Tags     []reportTagJsonView `json:"tags,omitempty"`
+	Warnings []string            `json:"warnings"`
+}
+
+type reportPeriodJsonView struct {
+	Since string `json:"since"`
+	Until string `json:"until"`
+	helper.TotalsView
+	*reportBalanceJsonView
+	Tags []reportTagJsonView `json:"tags,omitempty"`
+}
+
+type reportOpeningBalanceJsonView struct {
+	OpeningBalance     string `json:"opening_balance"`
+	OpeningBalanceMins int    `json:"opening_balance_mins"`
+}
+
+type reportBalanceJsonView struct {
+	Balance     string `json:"balance"`
+	BalanceMins int    `json:"balance_mins"`
+}
+
+type reportTagJsonView struct {
+	Tag       string `json:"tag"`
+	Total     string `json:"total"`
+	TotalMins int    `json:"total_mins"`
+}
+
 func (opt *Report) Help() string {
 	return `
 It aggregates the totals by period, and prints the respective values chronologically (from oldest to latest).
@@ -34,6 +73,19 @@ The default aggregation is by day, but you can choose other periods via the '--a
 
 The report skips all days (weeks, months, etc.) if no data is available for them.
 If you want a consecutive, chronological stream, you can use the '--fill' flag.
+
+With '--pivot', the totals are broken down by tag, with one column per tag (regardless of tag values).
+With '--pivot-tag', the totals are broken down by the values of one tag instead, e.g. '--pivot-tag project'.
+Since an entry can have multiple tags, the tag columns don’t necessarily add up to the total.
+
+With '--balance', the report includes the running balance of your overtime account, i.e. the accumulated difference between actual and should-total time up until the respective period.
+The balance starts from the 'opening_balance' setting in the config file (or from 0, if absent), so the last row shows the current balance.
+If the records are filtered, the balance still includes all records before the first reported one, regardless of the filter.
+
+With '--output json', the result is printed as JSON object, which always contains the should-total and the difference for every period.
+When pivoting, the totals per tag are included as well, both for every period and overall.
+With '--balance', every period contains the running balance, and the overall balance is the closing balance.
+The opening balance is the balance before the first period (or the balance of all records, if no records match).
 `
 }
 
@@ -49,12 +101,16 @@ func (opt *Report) Run(ctx app.Context) app.Error {
 	if err != nil {
 		return err
 	}
+	allRecords := records
 	now := ctx.Now()
-	records, fErr := opt.ApplyFilter(now, records)
+	records, fErr := opt.ApplyFilter(now, ctx.Config().NamedFilters.UnwrapOr(nil), records)
 	if fErr != nil {
 		return fErr
 	}
 	if len(records) == 0 {
+		if opt.IsJson() {
+			opt.printJson(ctx, nil, nil, nil, nil, opt.openingBalance(ctx, allRecords, nil))
+		}
 		return nil
 	}
 	nErr := opt.ApplyNow(now, records...)
@@ -72,13 +128,22 @@ func (opt *Report) Run(ctx app.Context) app.Error {
 			dates = allDatesRange(records[0].Date(), records[len(records)-1].Date())
 		}
 	}
+	pivotTags := opt.pivotTags(records)
+	openingBalance := opt.openingBalance(ctx, allRecords, records[0].Date())
+	if opt.IsJson() {
+		opt.printJson(ctx, records, recordGroups, dates, pivotTags, openingBalance)
+		return nil
+	}
 
 	// Table setup
 	numberOfValueColumns := func() int {
-		n := 1
+		n := 1 + len(pivotTags)
 		if opt.Diff {
 			n += 2
 		}
+		if opt.Balance {
+			n += 1
+		}
 		if opt.Chart {
 			n += 1
 		}
@@ -91,15 +156,22 @@ func (opt *Report) Run(ctx app.Context) app.Error {
 
 	// Header
 	aggregator.OnHeaderPrefix(table)
+	for _, t := range pivotTags {
+		table.CellR(t.ToString())
+	}
 	table.CellR("   Total")
 	if opt.Diff {
 		table.CellR("   Should").CellR("    Diff")
 	}
+	if opt.Balance {
+		table.CellR(" Balance")
+	}
 	if opt.Chart {
 		table.Skip(1)
 	}
 
 	// Rows
+	balance := openingBalance
 	hashesAlreadyProcessed := make(map[period.Hash]bool)
 	for _, date := range dates {
 		hash := aggregator.DateHash(date)
@@ -114,6 +186,13 @@ func (opt *Report) Run(ctx app.Context) app.Error {
 			continue
 		}
 
+		for _, d := range totalsByTags(pivotTags, rs) {
+			if d == nil {
+				table.Skip(1)
+				continue
+			}
+			table.CellR(serialiser.Duration(d))
+		}
 		total := service.Total(rs...)
 		table.CellR(serialiser.Duration(total))
 
@@ -122,16 +201,27 @@ func (opt *Report) Run(ctx app.Context) app.Error {
 			diff := service.Diff(should, total)
 			table.CellR(serialiser.ShouldTotal(should)).CellR(serialiser.SignedDuration(diff))
 		}
+		if opt.Balance {
+			balance = balance.Plus(service.Diff(service.ShouldTotalSum(rs...), total))
+			table.CellR(serialiser.SignedDuration(balance))
+		}
 		if opt.Chart {
 			table.CellL(" " + renderBar(opt.ChartResolution, total))
 		}
 	}
 
 	// Line
-	table.Skip(aggregator.NumberOfPrefixColumns()).Fill("=")
+	table.Skip(aggregator.NumberOfPrefixColumns())
+	for range pivotTags {
+		table.Fill("=")
+	}
+	table.Fill("=")
 	if opt.Diff {
 		table.Fill("=").Fill("=")
 	}
+	if opt.Balance {
+		table.Fill("=")
+	}
 	if opt.Chart {
 		table.Skip(1)
 	}
@@ -139,21 +229,114 @@ func (opt *Report) Run(ctx app.Context) app.Error {
 	// Footer
 	grandTotal := service.Total(records...)
 	table.Skip(aggregator.NumberOfPrefixColumns())
+	for _, d := range totalsByTags(pivotTags, records) {
+		table.CellR(serialiser.Duration(d))
+	}
 	table.CellR(serialiser.Duration(grandTotal))
 	if opt.Diff {
 		grandShould := service.ShouldTotalSum(records...)
 		grandDiff := service.Diff(grandShould, grandTotal)
 		table.CellR(serialiser.ShouldTotal(grandShould)).CellR(serialiser.SignedDuration(grandDiff))
 	}
+	if opt.Balance {
+		table.CellR(serialiser.SignedDuration(balance))
+	}
 	if opt.Chart {
 		table.Skip(1)
 	}
 
 	table.Collect(ctx.Print)
-	opt.WarnArgs.PrintWarnings(ctx, records, []service.UsageWarning{opt.NowArgs.GetWarning(), opt.DiffArgs.GetWarning(opt.FilterArgs)})
+	opt.WarnArgs.PrintWarnings(ctx, records, []service.UsageWarning{opt.NowArgs.GetWarning(), opt.diffWarning()})
 	return nil
 }
 
+func (opt *Report) printJson(ctx app.Context, records []klog.Record, recordGroups map[period.Hash][]klog.Record, dates []klog.Date, pivotTags []klog.Tag, openingBalance klog.Duration) {
+	aggregator := opt.aggregator()
+	view := reportJsonView{
+		Aggregation: map[string]string{"y": "year", "q": "quarter", "m": "month", "w": "week", "d": "day"}[opt.AggregateBy],
+		Periods:     []reportPeriodJsonView{},
+		TotalsView:  helper.NewTotalsView(service.Total(records...), service.ShouldTotalSum(records...)),
+		Tags:        newReportTagJsonViews(pivotTags, records),
+		Warnings:    opt.WarnArgs.GatherWarnings(ctx, records, []service.UsageWarning{opt.NowArgs.GetWarning(), opt.diffWarning()}),
+	}
+	balance := openingBalance
+	hashesAlreadyProcessed := make(map[period.Hash]bool)
+	for _, date := range dates {
+		hash := aggregator.DateHash(date)
+		if hashesAlreadyProcessed[hash] {
+			continue
+		}
+		hashesAlreadyProcessed[hash] = true
+		rs := recordGroups[hash]
+		p := aggregator.Period(date)
+		periodView := reportPeriodJsonView{
+			Since:      p.Since().ToStringWithFormat(klog.DefaultDateFormat()),
+			Until:      p.Until().ToStringWithFormat(klog.DefaultDateFormat()),
+			TotalsView: helper.NewTotalsView(service.Total(rs...), service.ShouldTotalSum(rs...)),
+			Tags:       newReportTagJsonViews(pivotTags, rs),
+		}
+		if opt.Balance {
+			balance = balance.Plus(service.Diff(service.ShouldTotalSum(rs...), service.Total(rs...)))
+			periodView.reportBalanceJsonView = newReportBalanceJsonView(balance)
+		}
+		view.Periods = append(view.Periods, periodView)
+	}
+	if opt.Balance {
+		view.reportOpeningBalanceJsonView = &reportOpeningBalanceJsonView{
+			OpeningBalance:     openingBalance.ToStringWithSign(),
+			OpeningBalanceMins: openingBalance.InMinutes(),
+		}
+		view.reportBalanceJsonView = newReportBalanceJsonView(balance)
+	}
+	helper.PrintJson(ctx, view)
+}
+
+// openingBalance returns the balance before the given date, i.e. the opening
+// balance from the config plus the differences of all records before that
+// date, regardless of the filter. Without date, all records are included.
+func (opt *Report) openingBalance(ctx app.Context, allRecords []klog.Record, before klog.Date) klog.Duration {
+	balance := ctx.Config().OpeningBalance.UnwrapOr(klog.NewDuration(0, 0))
+	if !opt.Balance {
+		return balance
+	}
+	for _, r := range allRecords {
+		if before != nil && r.Date().IsAfterOrEqual(before) {
+			continue
+		}
+		balance = balance.Plus(service.Diff(service.ShouldTotalSum(r), service.Total(r)))
+	}
+	return balance
+}
+
+func newReportBalanceJsonView(balance klog.Duration) *reportBalanceJsonView {
+	return &reportBalanceJsonView{
+		Balance:     balance.ToStringWithSign(),
+		BalanceMins: balance.InMinutes(),
+	}
+}
+
+// diffWarning returns the warning about entry-level filtering, which applies
+// to the running balance as well as to the diff.
+func (opt *Report) diffWarning() service.UsageWarning {
+	diffArgs := args.DiffArgs{Diff: opt.Diff || opt.Balance}
+	return diffArgs.GetWarning(opt.FilterArgs)
+}
+
+func newReportTagJsonViews(pivotTags []klog.Tag, rs []klog.Record) []reportTagJsonView {
+	var views []reportTagJsonView
+	for i, d := range totalsByTags(pivotTags, rs) {
+		if d == nil {
+			d = klog.NewDuration(0, 0)
+		}
+		views = append(views, reportTagJsonView{
+			Tag:       pivotTags[i].ToString(),
+			Total:     d.ToString(),
+			TotalMins: d.InMinutes(),
+		})
+	}
+	return views
+}
+
 func (opt *Report) canonicaliseOpts() app.Error {
 	if opt.AggregateBy == "" {
 		opt.AggregateBy = "d"
@@ -184,9 +367,76 @@ func (opt *Report) canonicaliseOpts() app.Error {
 	} else if opt.ChartResolution < 0 {
 		return app.NewErrorWithCode(app.LOGICAL_ERROR, "Invalid resolution", "The resolution must be a positive integer", nil)
 	}
+
+	if opt.PivotTag != "" {
+		opt.PivotTag = strings.ToLower(strings.TrimPrefix(opt.PivotTag, "#"))
+		if _, err := klog.NewTagFromString(opt.PivotTag); err != nil || strings.Contains(opt.PivotTag, "=") {
+			return app.NewErrorWithCode(app.GENERAL_ERROR, "Invalid tag", "The tag for '--pivot-tag' must be a tag name without value, e.g. 'project'", nil)
+		}
+		// When a pivot tag is specified, automatically assume --pivot
+		// to be given as well.
+		opt.Pivot = true
+	}
+	if opt.Pivot && opt.Chart {
+		return app.NewErrorWithCode(app.LOGICAL_ERROR, "Incompatible flags", "The '--pivot' flag cannot be combined with '--chart'", nil)
+	}
 	return nil
 }
 
+// pivotTags returns the tags that the totals are broken down by, sorted
+// alphanumerically. Without pivot tag, these are all tags without value,
+// otherwise they are all tags with a value for the pivot tag. Parents of
+// hierarchical tags are only included if they appear in the data themselves.
+func (opt *Report) pivotTags(rs []klog.Record) []klog.Tag {
+	if !opt.Pivot {
+		return nil
+	}
+	existing := klog.NewEmptyTagSet()
+	for _, r := range rs {
+		for _, e := range r.Entries() {
+			allTags := klog.Merge(r.Summary().Tags(), e.Summary().Tags())
+			for t := range allTags.ForLookup() {
+				existing.Put(t)
+			}
+		}
+	}
+	var tags []klog.Tag
+	stats, _ := service.AggregateTotalsByTags(rs...)
+	for _, s := range stats {
+		if !existing.Contains(s.Tag) {
+			continue
+		}
+		if opt.PivotTag == "" && s.Tag.Value() == "" {
+			tags = append(tags, s.Tag)
+		} else if opt.PivotTag != "" && s.Tag.Name() == opt.PivotTag && s.Tag.Value() != "" {
+			tags = append(tags, s.Tag)
+		}
+	}
+	return tags
+}
+
+// totalsByTags returns the totals of the tags, in the same order as the tags.
+// The total is nil if there are no entries for a tag. The time of hierarchical
+// tags is not rolled up to their parents, so that it’s not counted twice.
+func totalsByTags(tags []klog.Tag, rs []klog.Record) []klog.Duration {
+	totals := make([]klog.Duration, len(tags))
+	for _, r := range rs {
+		for _, e := range r.Entries() {
+			allTags := klog.Merge(r.Summary().Tags(), e.Summary().Tags())
+			for i, t := range tags {
+				if !allTags.Contains(t) {
+					continue
+				}
+				if totals[i] == nil {
+					totals[i] = klog.NewDuration(0, 0)
+				}
+				totals[i] = totals[i].Plus(e.Duration())
+			}
+		}
+	}
+	return totals
+}
+
 func (opt *Report) aggregator() report.Aggregator {
 	switch opt.AggregateBy {
 	case "y":
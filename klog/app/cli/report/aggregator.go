@@ -12,6 +12,7 @@ import (
 type Aggregator interface {
 	NumberOfPrefixColumns() int
 	DateHash(klog.Date) period.Hash
+	Period(klog.Date) period.Period
 	OnHeaderPrefix(*tf.Table)
 	OnRowPrefix(*tf.Table, klog.Date)
 }
@@ -26,6 +26,10 @@ func (a *dayAggregator) DateHash(date klog.Date) period.Hash {
 	return period.Hash(period.NewDayFromDate(date).Hash())
 }
 
+func (a *dayAggregator) Period(date klog.Date) period.Period {
+	return period.NewPeriod(date, date)
+}
+
 func (a *dayAggregator) OnHeaderPrefix(table *tf.Table) {
 	table.
 		CellL("    ").   // 2020
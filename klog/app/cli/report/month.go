@@ -25,6 +25,10 @@ func (a *monthAggregator) DateHash(date klog.Date) period.Hash {
 	return period.Hash(period.NewMonthFromDate(date).Hash())
 }
 
+func (a *monthAggregator) Period(date klog.Date) period.Period {
+	return period.NewMonthFromDate(date).Period()
+}
+
 func (a *monthAggregator) OnHeaderPrefix(table *tf.Table) {
 	table.
 		CellL("    "). // 2020
@@ -23,6 +23,10 @@ func (a *quarterAggregator) DateHash(date klog.Date) period.Hash {
 	return period.Hash(period.NewQuarterFromDate(date).Hash())
 }
 
+func (a *quarterAggregator) Period(date klog.Date) period.Period {
+	return period.NewQuarterFromDate(date).Period()
+}
+
 func (a *quarterAggregator) OnHeaderPrefix(table *tf.Table) {
 	table.
 		CellL("    "). // 2020
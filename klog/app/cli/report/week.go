@@ -23,6 +23,10 @@ func (a *weekAggregator) DateHash(date klog.Date) period.Hash {
 	return period.Hash(period.NewWeekFromDate(date).Hash())
 }
 
+func (a *weekAggregator) Period(date klog.Date) period.Period {
+	return period.NewWeekFromDate(date).Period()
+}
+
 func (a *weekAggregator) OnHeaderPrefix(table *tf.Table) {
 	table.
 		CellL("    ").    // 2020
@@ -21,6 +21,10 @@ func (a *yearAggregator) DateHash(date klog.Date) period.Hash {
 	return period.Hash(period.NewYearFromDate(date).Hash())
 }
 
+func (a *yearAggregator) Period(date klog.Date) period.Period {
+	return period.NewYearFromDate(date).Period()
+}
+
 func (a *yearAggregator) OnHeaderPrefix(table *tf.Table) {
 	table.
 		CellL("    ") // 2020
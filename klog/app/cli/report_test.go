@@ -529,3 +529,269 @@ func TestReportWithScaledChart(t *testing.T) {
 		assert.Equal(t, "Invalid resolution", err.Error())
 	})
 }
+
+func TestReportAsJson(t *testing.T) {
+	state, err := NewTestingContext()._SetRecords(`
+2024-01-30 (8h!)
+	8h30m
+
+2024-02-01 (8h!)
+	7h
+
+2024-02-29
+	1h
+`)._Run((&Report{
+		AggregateBy: "month",
+		OutputArgs:  args.OutputArgs{Output: "json"},
+	}).Run)
+	require.Nil(t, err)
+	assert.Equal(t, "\n"+`{"aggregation":"month","periods":[`+
+		`{"since":"2024-01-01","until":"2024-01-31","total":"8h30m","total_mins":510,"should_total":"8h!","should_total_mins":480,"diff":"+30m","diff_mins":30},`+
+		`{"since":"2024-02-01","until":"2024-02-29","total":"8h","total_mins":480,"should_total":"8h!","should_total_mins":480,"diff":"0m","diff_mins":0}`+
+		`],"total":"16h30m","total_mins":990,"should_total":"16h!","should_total_mins":960,"diff":"+30m","diff_mins":30,"warnings":null}`+"\n", state.printBuffer)
+}
+
+func TestReportOfEmptyInputAsJson(t *testing.T) {
+	state, err := NewTestingContext()._SetRecords(``)._Run((&Report{OutputArgs: args.OutputArgs{Output: "json"}}).Run)
+	require.Nil(t, err)
+	assert.Equal(t, "\n"+`{"aggregation":"day","periods":[],"total":"0m","total_mins":0,"should_total":"0m!","should_total_mins":0,"diff":"0m","diff_mins":0,"warnings":null}`+"\n", state.printBuffer)
+}
+
+func TestReportWithPivotByTags(t *testing.T) {
+	/*
+		Aspects tested:
+		- One column per tag (without value), sorted alphanumerically
+		- Empty cells for periods without entries for a tag
+		- Column totals in the footer
+		- Entries with several tags count towards all of them
+	*/
+	state, err := NewTestingContext()._SetRecords(`
+2024-01-01
+	2h #acme #project=alpha
+	1h #beta
+	30m
+
+2024-01-03
+	1h #project=beta #acme
+
+2024-01-09
+	4h #beta
+
+2024-01-17
+	3h #acme #project=alpha
+	1h #project=beta
+`)._Run((&Report{AggregateBy: "week", Pivot: true}).Run)
+	require.Nil(t, err)
+	assert.Equal(t, `
+              #acme #beta #project    Total
+2024  Week  1    3h    1h       3h    4h30m
+      Week  2          4h                4h
+      Week  3    3h             4h       4h
+              ===== ===== ======== ========
+                 6h    5h       7h   12h30m
+`, state.printBuffer)
+}
+
+func TestReportWithPivotByTagValues(t *testing.T) {
+	state, err := NewTestingContext()._SetRecords(`
+2024-01-01 (4h!)
+	2h #acme #project=alpha
+	1h #beta
+	30m
+
+2024-01-09 (4h!)
+	4h #beta
+
+2024-01-17 (4h!)
+	3h #acme #project=alpha
+	1h #project=beta
+`)._Run((&Report{AggregateBy: "week", PivotTag: "#Project", DiffArgs: args.DiffArgs{Diff: true}}).Run)
+	require.Nil(t, err)
+	assert.Equal(t, `
+              #project=alpha #project=beta    Total    Should     Diff
+2024  Week  1             2h                  3h30m       4h!     -30m
+      Week  2                                    4h       4h!       0m
+      Week  3             3h            1h       4h       4h!       0m
+              ============== ============= ======== ========= ========
+                          5h            1h   11h30m      12h!     -30m
+`, state.printBuffer)
+}
+
+func TestReportWithPivotByHierarchicalTags(t *testing.T) {
+	ctx := NewTestingContext()._SetRecords(`
+2024-01-01
+	2h #acme #project=alpha
+	1h #acme/backend #project/sub=alpha
+	30m #acme/backend/api
+
+2024-01-09
+	4h #acme/frontend #project=beta
+`)
+
+	t.Run("By tags", func(t *testing.T) {
+		// The parents are not rolled up, so that no time is counted twice.
+		state, err := ctx._Run((&Report{AggregateBy: "week", Pivot: true}).Run)
+		require.Nil(t, err)
+		assert.Equal(t, `
+              #acme #acme/backend #acme/backend/api #acme/frontend #project #project/sub    Total
+2024  Week  1    2h            1h               30m                      2h           1h    3h30m
+      Week  2                                                   4h       4h                    4h
+              ===== ============= ================= ============== ======== ============ ========
+                 2h            1h               30m             4h       6h           1h    7h30m
+`, state.printBuffer)
+	})
+
+	t.Run("By tag values", func(t *testing.T) {
+		state, err := ctx._Run((&Report{AggregateBy: "week", PivotTag: "project"}).Run)
+		require.Nil(t, err)
+		assert.Equal(t, `
+              #project=alpha #project=beta    Total
+2024  Week  1             2h                  3h30m
+      Week  2                           4h       4h
+              ============== ============= ========
+                          2h            4h    7h30m
+`, state.printBuffer)
+	})
+}
+
+func TestReportWithPivotAsJson(t *testing.T) {
+	state, err := NewTestingContext()._SetRecords(`
+2024-01-30
+	8h #acme
+
+2024-02-01
+	7h #beta
+`)._Run((&Report{
+		AggregateBy: "month",
+		Pivot:       true,
+		OutputArgs:  args.OutputArgs{Output: "json"},
+	}).Run)
+	require.Nil(t, err)
+	assert.Equal(t, "\n"+`{"aggregation":"month","periods":[`+
+		`{"since":"2024-01-01","until":"2024-01-31","total":"8h","total_mins":480,"should_total":"0m!","should_total_mins":0,"diff":"+8h","diff_mins":480,"tags":[{"tag":"#acme","total":"8h","total_mins":480},{"tag":"#beta","total":"0m","total_mins":0}]},`+
+		`{"since":"2024-02-01","until":"2024-02-29","total":"7h","total_mins":420,"should_total":"0m!","should_total_mins":0,"diff":"+7h","diff_mins":420,"tags":[{"tag":"#acme","total":"0m","total_mins":0},{"tag":"#beta","total":"7h","total_mins":420}]}`+
+		`],"total":"15h","total_mins":900,"should_total":"0m!","should_total_mins":0,"diff":"+15h","diff_mins":900,"tags":[{"tag":"#acme","total":"8h","total_mins":480},{"tag":"#beta","total":"7h","total_mins":420}],"warnings":null}`+"\n", state.printBuffer)
+}
+
+func TestReportWithPivotRejectsInvalidFlags(t *testing.T) {
+	for _, r := range []Report{
+		{Pivot: true, Chart: true},
+		{PivotTag: "project=alpha"},
+		{PivotTag: "#"},
+	} {
+		_, err := NewTestingContext()._SetRecords(`
+2024-01-30
+	8h #acme
+`)._Run(r.Run)
+		require.Error(t, err)
+	}
+}
+
+func TestReportWithBalance(t *testing.T) {
+	state, err := NewTestingContext()._SetRecords(`
+2018-12-09 (8h!)
+	8h
+
+2018-12-26 (1h30m!)
+	2h
+
+2018-12-31 (30m!)
+	15m
+
+2019-01-02 (2h!)
+	3h
+
+2019-01-08 (2h!)
+`)._Run((&Report{AggregateBy: "week", DiffArgs: args.DiffArgs{Diff: true}, Balance: true, Fill: true}).Run)
+	require.Nil(t, err)
+	assert.Equal(t, `
+                 Total    Should     Diff  Balance
+2018  Week 49       8h       8h!       0m       0m
+      Week 50                                     
+      Week 51                                     
+      Week 52       2h    1h30m!     +30m     +30m
+2019  Week  1    3h15m    2h30m!     +45m   +1h15m
+      Week  2       0m       2h!      -2h     -45m
+              ======== ========= ======== ========
+                13h15m      14h!     -45m     -45m
+`, state.printBuffer)
+}
+
+func TestReportWithBalanceStartsFromOpeningBalance(t *testing.T) {
+	state, err := NewTestingContext()._SetRecords(`
+2024-01-30 (8h!)
+	8h30m
+
+2024-02-01 (8h!)
+	7h
+`)._SetFileConfig(`
+opening_balance = +10h
+`)._Run((&Report{AggregateBy: "month", Balance: true}).Run)
+	require.Nil(t, err)
+	assert.Equal(t, `
+            Total  Balance
+2024 Jan    8h30m  +10h30m
+     Feb       7h   +9h30m
+         ======== ========
+           15h30m   +9h30m
+`, state.printBuffer)
+}
+
+func TestReportWithBalanceAsJson(t *testing.T) {
+	state, err := NewTestingContext()._SetRecords(`
+2024-01-30 (8h!)
+	8h30m
+
+2024-02-01 (8h!)
+	7h
+`)._SetFileConfig(`
+opening_balance = -1h
+`)._Run((&Report{
+		AggregateBy: "month",
+		Balance:     true,
+		OutputArgs:  args.OutputArgs{Output: "json"},
+	}).Run)
+	require.Nil(t, err)
+	assert.Equal(t, "\n"+`{"aggregation":"month","periods":[`+
+		`{"since":"2024-01-01","until":"2024-01-31","total":"8h30m","total_mins":510,"should_total":"8h!","should_total_mins":480,"diff":"+30m","diff_mins":30,"balance":"-30m","balance_mins":-30},`+
+		`{"since":"2024-02-01","until":"2024-02-29","total":"7h","total_mins":420,"should_total":"8h!","should_total_mins":480,"diff":"-1h","diff_mins":-60,"balance":"-1h30m","balance_mins":-90}`+
+		`],"total":"15h30m","total_mins":930,"should_total":"16h!","should_total_mins":960,"diff":"-30m","diff_mins":-30,"opening_balance":"-1h","opening_balance_mins":-60,"balance":"-1h30m","balance_mins":-90,"warnings":null}`+"\n", state.printBuffer)
+}
+
+func TestReportWithBalanceIncludesRecordsBeforeFilter(t *testing.T) {
+	ctx := NewTestingContext()._SetRecords(`
+2024-01-30 (8h!)
+	8h30m
+
+2024-02-01 (8h!)
+	7h
+
+2024-02-02 (8h!)
+	9h
+`)._SetFileConfig(`
+opening_balance = +10h
+`)
+
+	t.Run("Table", func(t *testing.T) {
+		state, err := ctx._Run((&Report{AggregateBy: "day", Balance: true, FilterArgs: args.FilterArgs{Since: klog.Ɀ_Date_(2024, 2, 1)}}).Run)
+		require.Nil(t, err)
+		assert.Equal(t, `
+                       Total  Balance
+2024 Feb    Thu  1.       7h   +9h30m
+            Fri  2.       9h  +10h30m
+                    ======== ========
+                         16h  +10h30m
+`, state.printBuffer)
+	})
+
+	t.Run("JSON without matching records", func(t *testing.T) {
+		state, err := ctx._Run((&Report{
+			Balance:    true,
+			FilterArgs: args.FilterArgs{Since: klog.Ɀ_Date_(2024, 3, 1)},
+			OutputArgs: args.OutputArgs{Output: "json"},
+		}).Run)
+		require.Nil(t, err)
+		assert.Equal(t, "\n"+`{"aggregation":"day","periods":[],"total":"0m","total_mins":0,"should_total":"0m!","should_total_mins":0,"diff":"0m","diff_mins":0,`+
+			`"opening_balance":"+10h30m","opening_balance_mins":630,"balance":"+10h30m","balance_mins":630,"warnings":null}`+"\n", state.printBuffer)
+	})
+}
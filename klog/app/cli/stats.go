@@ -0,0 +1,158 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/jotaen/klog/klog"
+	"github.com/jotaen/klog/klog/app"
+	"github.com/jotaen/klog/klog/app/cli/args"
+	"github.com/jotaen/klog/klog/app/cli/helper"
+	"github.com/jotaen/klog/klog/app/cli/prettify"
+	"github.com/jotaen/klog/klog/service"
+	tf "github.com/jotaen/klog/lib/terminalformat"
+)
+
+type Stats struct {
+	args.FilterArgs
+	args.NowArgs
+	args.DecimalArgs
+	args.WarnArgs
+	args.NoStyleArgs
+	args.OutputArgs
+	args.InputFilesArgs
+}
+
+type statsJsonView struct {
+	Days                    int      `json:"days"`
+	Total                   string   `json:"total"`
+	TotalMins               int      `json:"total_mins"`
+	AverageDailyTotal       string   `json:"average_daily_total"`
+	AverageDailyTotalMins   int      `json:"average_daily_total_mins"`
+	MedianDailyTotal        string   `json:"median_daily_total"`
+	MedianDailyTotalMins    int      `json:"median_daily_total_mins"`
+	TypicalStartTime        *string  `json:"typical_start_time"`
+	TypicalEndTime          *string  `json:"typical_end_time"`
+	LongestStreak           int      `json:"longest_streak"`
+	BusiestWeekday          *string  `json:"busiest_weekday"`
+	BusiestWeekdayTotal     string   `json:"busiest_weekday_total"`
+	BusiestWeekdayTotalMins int      `json:"busiest_weekday_total_mins"`
+	AverageEntriesPerDay    float64  `json:"average_entries_per_day"`
+	RangesCount             int      `json:"ranges_count"`
+	DurationsCount          int      `json:"durations_count"`
+	OpenRangesCount         int      `json:"open_ranges_count"`
+	Warnings                []string `json:"warnings"`
+}
+
+func (opt *Stats) Help() string {
+	return `
+The statistics are computed over all days that contain at least one entry. Records with the same date count as one day.
+
+- The average and median daily totals are based on the total time per day.
+- The typical start and end times are the medians of the earliest start time and the latest end time per day. Only time ranges are taken into account for these.
+- The longest streak is the highest number of consecutive calendar days with entries.
+- The busiest weekday is the weekday with the highest total time.
+- The share of time ranges and durations refers to the number of entries.
+
+Use the filter flags to restrict the statistics to a certain period, e.g. '--period 2024' or '--last-quarter'.
+
+With '--output json', the result is printed as JSON object. Values that cannot be determined (e.g., the typical start time if there are no time ranges) are 'null'.
+`
+}
+
+func (opt *Stats) Run(ctx app.Context) app.Error {
+	opt.DecimalArgs.Apply(&ctx)
+	opt.NoStyleArgs.Apply(&ctx)
+	styler, serialiser := ctx.Serialise()
+	records, err := ctx.ReadInputs(opt.File...)
+	if err != nil {
+		return err
+	}
+	now := ctx.Now()
+	records, fErr := opt.ApplyFilter(now, ctx.Config().NamedFilters.UnwrapOr(nil), records)
+	if fErr != nil {
+		return fErr
+	}
+	nErr := opt.ApplyNow(now, records...)
+	if nErr != nil {
+		return nErr
+	}
+	stats := service.NewStats(records...)
+	if opt.IsJson() {
+		helper.PrintJson(ctx, newStatsJsonView(stats, opt.WarnArgs.GatherWarnings(ctx, records, []service.UsageWarning{opt.NowArgs.GetWarning()})))
+		return nil
+	}
+	subdued := styler.Props(tf.StyleProps{Color: tf.TEXT_SUBDUED})
+	orNone := func(t klog.Time) string {
+		if t == nil {
+			return subdued.Format("-")
+		}
+		return serialiser.Time(t)
+	}
+	share := func(count int) string {
+		if stats.EntryCount() == 0 {
+			return fmt.Sprintf("%d", count)
+		}
+		return fmt.Sprintf("%d ", count) + subdued.Format(fmt.Sprintf("(%.0f%%)", 100*float64(count)/float64(stats.EntryCount())))
+	}
+	busiestWeekday := subdued.Format("-")
+	if stats.BusiestWeekday != 0 {
+		busiestWeekday = prettify.PrettyDay(stats.BusiestWeekday) + " " + subdued.Format("("+serialiser.Duration(stats.BusiestWeekdayTotal)+")")
+	}
+	table := tf.NewTable(2, " ")
+	table.CellL("Days:").CellL(fmt.Sprintf("%d", stats.Days))
+	table.CellL("Total:").CellL(serialiser.Duration(stats.Total))
+	table.CellL("Average per day:").CellL(serialiser.Duration(stats.AverageDailyTotal))
+	table.CellL("Median per day:").CellL(serialiser.Duration(stats.MedianDailyTotal))
+	table.CellL("Typical start:").CellL(orNone(stats.TypicalStartTime))
+	table.CellL("Typical end:").CellL(orNone(stats.TypicalEndTime))
+	table.CellL("Longest streak:").CellL(fmt.Sprintf("%d day%s", stats.LongestStreak, func() string {
+		if stats.LongestStreak == 1 {
+			return ""
+		}
+		return "s"
+	}()))
+	table.CellL("Busiest weekday:").CellL(busiestWeekday)
+	table.CellL("Entries per day:").CellL(fmt.Sprintf("%.1f", stats.AverageEntriesPerDay))
+	table.CellL("Time ranges:").CellL(share(stats.RangeCount))
+	table.CellL("Durations:").CellL(share(stats.DurationCount))
+	if stats.OpenRangeCount > 0 {
+		table.CellL("Open ranges:").CellL(share(stats.OpenRangeCount))
+	}
+	table.Collect(ctx.Print)
+	opt.WarnArgs.PrintWarnings(ctx, records, []service.UsageWarning{opt.NowArgs.GetWarning()})
+	return nil
+}
+
+func newStatsJsonView(stats service.Stats, warnings []string) statsJsonView {
+	optionalTime := func(t klog.Time) *string {
+		if t == nil {
+			return nil
+		}
+		s := t.ToString()
+		return &s
+	}
+	view := statsJsonView{
+		Days:                    stats.Days,
+		Total:                   stats.Total.ToString(),
+		TotalMins:               stats.Total.InMinutes(),
+		AverageDailyTotal:       stats.AverageDailyTotal.ToString(),
+		AverageDailyTotalMins:   stats.AverageDailyTotal.InMinutes(),
+		MedianDailyTotal:        stats.MedianDailyTotal.ToString(),
+		MedianDailyTotalMins:    stats.MedianDailyTotal.InMinutes(),
+		TypicalStartTime:        optionalTime(stats.TypicalStartTime),
+		TypicalEndTime:          optionalTime(stats.TypicalEndTime),
+		LongestStreak:           stats.LongestStreak,
+		BusiestWeekdayTotal:     stats.BusiestWeekdayTotal.ToString(),
+		BusiestWeekdayTotalMins: stats.BusiestWeekdayTotal.InMinutes(),
+		AverageEntriesPerDay:    stats.AverageEntriesPerDay,
+		RangesCount:             stats.RangeCount,
+		DurationsCount:          stats.DurationCount,
+		OpenRangesCount:         stats.OpenRangeCount,
+		Warnings:                warnings,
+	}
+	if stats.BusiestWeekday != 0 {
+		weekday := prettify.PrettyDay(stats.BusiestWeekday)
+		view.BusiestWeekday = &weekday
+	}
+	return view
+}
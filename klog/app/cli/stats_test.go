@@ -0,0 +1,92 @@
+package cli
+
+import (
+	"testing"
+
+	"github.com/jotaen/klog/klog/app/cli/args"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStatsOfEmptyInput(t *testing.T) {
+	state, err := NewTestingContext()._SetRecords(``)._Run((&Stats{}).Run)
+	require.Nil(t, err)
+	assert.Equal(t, `
+Days:            0     
+Total:           0m    
+Average per day: 0m    
+Median per day:  0m    
+Typical start:   -     
+Typical end:     -     
+Longest streak:  0 days
+Busiest weekday: -     
+Entries per day: 0.0   
+Time ranges:     0     
+Durations:       0     
+`, state.printBuffer)
+}
+
+func TestStats(t *testing.T) {
+	state, err := NewTestingContext()._SetRecords(`
+2024-01-01
+	8:00 - 12:00
+	13:00 - 17:00
+
+2024-01-02
+	9:00 - 11:00
+	1h
+	15:00 - 18:00
+
+2024-01-08
+	9h
+`)._Run((&Stats{}).Run)
+	require.Nil(t, err)
+	assert.Equal(t, `
+Days:            3           
+Total:           23h         
+Average per day: 7h40m       
+Median per day:  8h          
+Typical start:   8:30        
+Typical end:     17:30       
+Longest streak:  2 days      
+Busiest weekday: Monday (17h)
+Entries per day: 2.0         
+Time ranges:     4 (67%)     
+Durations:       2 (33%)     
+`, state.printBuffer)
+}
+
+func TestStatsAsJson(t *testing.T) {
+	state, err := NewTestingContext()._SetRecords(`
+2024-01-01
+	8:00 - 12:00
+
+2024-01-02
+	2h
+`)._Run((&Stats{OutputArgs: args.OutputArgs{Output: "json"}}).Run)
+	require.Nil(t, err)
+	assert.Equal(t, "\n"+`{"days":2,"total":"6h","total_mins":360,"average_daily_total":"3h","average_daily_total_mins":180,"median_daily_total":"3h","median_daily_total_mins":180,"typical_start_time":"8:00","typical_end_time":"12:00","longest_streak":2,"busiest_weekday":"Monday","busiest_weekday_total":"4h","busiest_weekday_total_mins":240,"average_entries_per_day":1,"ranges_count":1,"durations_count":1,"open_ranges_count":0,"warnings":null}`+"\n", state.printBuffer)
+}
+
+func TestStatsWithOpenRange(t *testing.T) {
+	state, err := NewTestingContext()._SetRecords(`
+2024-01-01
+	7:30 - 8:00
+	9:00 - ?
+`)._SetNow(2024, 1, 1, 10, 0)._Run((&Stats{WarnArgs: args.WarnArgs{NoWarn: true}}).Run)
+	require.Nil(t, err)
+	assert.Equal(t, `
+Days:            1           
+Total:           30m         
+Average per day: 30m         
+Median per day:  30m         
+Typical start:   7:30        
+Typical end:     8:00        
+Longest streak:  1 day       
+Busiest weekday: Monday (30m)
+Entries per day: 2.0         
+Time ranges:     1 (50%)     
+Durations:       0 (0%)      
+Open ranges:     1 (50%)     
+`, state.printBuffer)
+}
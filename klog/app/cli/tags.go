@@ -2,25 +2,60 @@ package cli
 
 import (
 	"fmt"
+	"strings"
 
+	"github.com/jotaen/klog/klog"
 	"github.com/jotaen/klog/klog/app"
 	"github.com/jotaen/klog/klog/app/cli/args"
+	"github.com/jotaen/klog/klog/app/cli/helper"
 	"github.com/jotaen/klog/klog/service"
 	tf "github.com/jotaen/klog/lib/terminalformat"
 )
 
 type Tags struct {
-	Values       bool `name:"values" short:"v" help:"Display breakdown of tag values (if the data contains any; e.g.: '#tag=value')."`
-	Count        bool `name:"count" short:"c" help:"Display the number of matching entries per tag."`
-	WithUntagged bool `name:"with-untagged" short:"u" help:"Display remainder of any untagged entries"`
+	Values       bool              `name:"values" short:"v" help:"Display breakdown of tag values (if the data contains any; e.g.: '#tag=value')."`
+	Count        bool              `name:"count" short:"c" help:"Display the number of matching entries per tag."`
+	WithUntagged bool              `name:"with-untagged" short:"u" help:"Display remainder of any untagged entries"`
+	Patterns     []klog.TagPattern `name:"pattern" short:"p" placeholder:"TAG" help:"Aggregate by this tag pattern instead of by the individual tags. Use '*' as wildcard, e.g. 'client-*' or 'ticket=PROJ-*'. Can be repeated."`
 	args.FilterArgs
 	args.NowArgs
 	args.DecimalArgs
 	args.WarnArgs
 	args.NoStyleArgs
+	args.OutputArgs
 	args.InputFilesArgs
 }
 
+type tagsJsonView struct {
+	Tags     []tagJsonView        `json:"tags"`
+	Patterns []tagPatternJsonView `json:"patterns,omitempty"`
+	Untagged untaggedJsonView     `json:"untagged"`
+	Warnings []string             `json:"warnings"`
+}
+
+type tagPatternJsonView struct {
+	Pattern   string        `json:"pattern"`
+	Total     string        `json:"total"`
+	TotalMins int           `json:"total_mins"`
+	Count     int           `json:"count"`
+	Tags      []tagJsonView `json:"tags"`
+}
+
+type tagJsonView struct {
+	Tag       string `json:"tag"`
+	Name      string `json:"name"`
+	Value     string `json:"value"`
+	Total     string `json:"total"`
+	TotalMins int    `json:"total_mins"`
+	Count     int    `json:"count"`
+}
+
+type untaggedJsonView struct {
+	Total     string `json:"total"`
+	TotalMins int    `json:"total_mins"`
+	Count     int    `json:"count"`
+}
+
 func (opt *Tags) Help() string {
 	return ` 
 If a tag appears in the overall record summary, then all of the record’s entries match.
@@ -30,7 +65,16 @@ If tags are specified redundantly in the data, the respective time is still coun
 If you use tags with values (e.g., '#tag=value'), then these also match against the base tag (e.g., '#tag').
 You can use the '--values' flag to display an additional breakdown by tag value.
 
+Tag names can be hierarchical, with the levels separated by '/' (e.g., '#acme/backend/api').
+Then, the time is rolled up to all parent tags (e.g., '#acme/backend' and '#acme'), and the child tags are displayed indented below their parents.
+Likewise, when filtering by a parent tag (e.g., '#acme'), all entries with child tags match as well.
+
+With '--pattern', the time is aggregated by tag patterns instead, where '*' is a wildcard (e.g. '--pattern client-*').
+Every entry is counted once per pattern, even if several of its tags match. With '--values', there is an additional breakdown by the matching tags.
+
 Note that tag names are case-insensitive (e.g., '#tag' is the same as '#TAG'), whereas tag values are case-sensitive (so '#tag=value' is different from '#tag=VALUE').
+
+With '--output json', the result is printed as JSON object, which always contains the tag value breakdown, the counts and the untagged remainder.
 `
 }
 
@@ -43,7 +87,7 @@ func (opt *Tags) Run(ctx app.Context) app.Error {
 		return err
 	}
 	now := ctx.Now()
-	records, fErr := opt.ApplyFilter(now, records)
+	records, fErr := opt.ApplyFilter(now, ctx.Config().NamedFilters.UnwrapOr(nil), records)
 	if fErr != nil {
 		return fErr
 	}
@@ -52,6 +96,39 @@ func (opt *Tags) Run(ctx app.Context) app.Error {
 		return nErr
 	}
 	tagStats, untagged := service.AggregateTotalsByTags(records...)
+	var patternStats []service.TagPatternStats
+	if len(opt.Patterns) > 0 {
+		patternStats = service.AggregateTotalsByTagPatterns(opt.Patterns, records...)
+	}
+	if opt.IsJson() {
+		view := tagsJsonView{
+			Tags: []tagJsonView{},
+			Untagged: untaggedJsonView{
+				Total:     untagged.Total.ToString(),
+				TotalMins: untagged.Total.InMinutes(),
+				Count:     untagged.Count,
+			},
+			Warnings: opt.WarnArgs.GatherWarnings(ctx, records, []service.UsageWarning{opt.NowArgs.GetWarning()}),
+		}
+		for _, t := range tagStats {
+			view.Tags = append(view.Tags, newTagJsonView(t))
+		}
+		for _, p := range patternStats {
+			pView := tagPatternJsonView{
+				Pattern:   p.Pattern.ToString(),
+				Total:     p.Total.ToString(),
+				TotalMins: p.Total.InMinutes(),
+				Count:     p.Count,
+				Tags:      []tagJsonView{},
+			}
+			for _, t := range p.Tags {
+				pView.Tags = append(pView.Tags, newTagJsonView(t))
+			}
+			view.Patterns = append(view.Patterns, pView)
+		}
+		helper.PrintJson(ctx, view)
+		return nil
+	}
 	numberOfColumns := 2
 	if opt.Values {
 		numberOfColumns++
@@ -63,26 +140,54 @@ func (opt *Tags) Run(ctx app.Context) app.Error {
 		return styler.Props(tf.StyleProps{Color: tf.TEXT_SUBDUED}).Format(fmt.Sprintf(" (%d)", c))
 	}
 	table := tf.NewTable(numberOfColumns, " ")
-	for _, t := range tagStats {
-		totalString := serialiser.Duration(t.Total)
-		if t.Tag.Value() == "" {
-			table.CellL("#" + t.Tag.Name())
-			table.CellL(totalString)
-			if opt.Values {
-				table.Skip(1)
-			}
-			if opt.Count {
-				table.CellL(countString(t.Count))
-			}
-		} else if opt.Values {
-			table.CellL(" " + styler.Props(tf.StyleProps{Color: tf.TEXT_SUBDUED}).Format(t.Tag.Value()))
+	for _, p := range patternStats {
+		table.CellL(p.Pattern.ToString())
+		table.CellL(serialiser.Duration(p.Total))
+		if opt.Values {
+			table.Skip(1)
+		}
+		if opt.Count {
+			table.CellL(countString(p.Count))
+		}
+		if !opt.Values {
+			continue
+		}
+		for _, t := range p.Tags {
+			table.CellL(" " + styler.Props(tf.StyleProps{Color: tf.TEXT_SUBDUED}).Format(t.Tag.ToString()))
 			table.Skip(1)
-			table.CellL(totalString)
+			table.CellL(serialiser.Duration(t.Total))
 			if opt.Count {
 				table.CellL(countString(t.Count))
 			}
 		}
 	}
+	// The patterns replace the individual tags.
+	if patternStats == nil {
+		for _, t := range tagStats {
+			totalString := serialiser.Duration(t.Total)
+			// Hierarchical tags are indented below their parents, and only
+			// their last segment is displayed.
+			indentation := strings.Repeat("  ", len(t.Tag.Parents()))
+			if t.Tag.Value() == "" {
+				name := t.Tag.Name()[strings.LastIndex(t.Tag.Name(), klog.TagSeparator)+1:]
+				table.CellL(indentation + "#" + name)
+				table.CellL(totalString)
+				if opt.Values {
+					table.Skip(1)
+				}
+				if opt.Count {
+					table.CellL(countString(t.Count))
+				}
+			} else if opt.Values {
+				table.CellL(indentation + " " + styler.Props(tf.StyleProps{Color: tf.TEXT_SUBDUED}).Format(t.Tag.Value()))
+				table.Skip(1)
+				table.CellL(totalString)
+				if opt.Count {
+					table.CellL(countString(t.Count))
+				}
+			}
+		}
+	}
 	if opt.WithUntagged {
 		table.CellL("(untagged)")
 		table.CellL(serialiser.Duration(untagged.Total))
@@ -97,3 +202,14 @@ func (opt *Tags) Run(ctx app.Context) app.Error {
 	opt.WarnArgs.PrintWarnings(ctx, records, []service.UsageWarning{opt.NowArgs.GetWarning()})
 	return nil
 }
+
+func newTagJsonView(t service.TagStats) tagJsonView {
+	return tagJsonView{
+		Tag:       t.Tag.ToString(),
+		Name:      t.Tag.Name(),
+		Value:     t.Tag.Value(),
+		Total:     t.Total.ToString(),
+		TotalMins: t.Total.InMinutes(),
+		Count:     t.Count,
+	}
+}
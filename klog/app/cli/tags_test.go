@@ -1,6 +1,8 @@
 package cli
 
 import (
+	"github.com/jotaen/klog/klog"
+	"github.com/jotaen/klog/klog/app/cli/args"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"testing"
@@ -285,3 +287,119 @@ func TestPrintTagsOverviewWithoutValueGrouping(t *testing.T) {
 #ticket 4h
 `, state.printBuffer)
 }
+
+func TestPrintHierarchicalTags(t *testing.T) {
+	ctx := NewTestingContext()._SetRecords(`
+1995-03-17
+	2h #acme/backend/api
+	1h #acme/backend=db
+	30m #acme/frontend #acme/backend
+	1h #acme-corp
+`)
+
+	t.Run("Without argument", func(t *testing.T) {
+		state, err := ctx._Run((&Tags{}).Run)
+		require.Nil(t, err)
+		assert.Equal(t, `
+#acme-corp  1h   
+#acme       3h30m
+  #backend  3h30m
+    #api    2h   
+  #frontend 30m  
+`, state.printBuffer)
+	})
+
+	t.Run("With values", func(t *testing.T) {
+		state, err := ctx._Run((&Tags{Values: true}).Run)
+		require.Nil(t, err)
+		assert.Equal(t, `
+#acme-corp  1h      
+#acme       3h30m   
+  #backend  3h30m   
+   db             1h
+    #api    2h      
+  #frontend 30m     
+`, state.printBuffer)
+	})
+
+	t.Run("Filtered by parent tag", func(t *testing.T) {
+		state, err := ctx._Run((&Tags{FilterArgs: args.FilterArgs{Filter: "#acme/backend"}}).Run)
+		require.Nil(t, err)
+		assert.Equal(t, `
+#acme       3h30m
+  #backend  3h30m
+    #api    2h   
+  #frontend 30m  
+`, state.printBuffer)
+	})
+}
+
+func TestPrintTagsAsJson(t *testing.T) {
+	state, err := NewTestingContext()._SetRecords(`
+1995-03-17
+#sports
+	3h #badminton
+	1h #running=home-trail
+	1h untagged
+
+1995-03-18
+	2h
+`)._Run((&Tags{OutputArgs: args.OutputArgs{Output: "json"}}).Run)
+	require.Nil(t, err)
+	assert.Equal(t, "\n"+`{"tags":[`+
+		`{"tag":"#badminton","name":"badminton","value":"","total":"3h","total_mins":180,"count":1},`+
+		`{"tag":"#running","name":"running","value":"","total":"1h","total_mins":60,"count":1},`+
+		`{"tag":"#running=home-trail","name":"running","value":"home-trail","total":"1h","total_mins":60,"count":1},`+
+		`{"tag":"#sports","name":"sports","value":"","total":"5h","total_mins":300,"count":3}`+
+		`],"untagged":{"total":"2h","total_mins":120,"count":1},"warnings":null}`+"\n", state.printBuffer)
+}
+
+func TestPrintTagsByPattern(t *testing.T) {
+	ctx := NewTestingContext()._SetRecords(`
+1995-03-17
+#client-acme
+	3h #ticket=PROJ-1
+	1h #ticket=OTHER-1
+
+1995-03-18
+	2h #client-globex #client-initech #ticket=PROJ-2
+	1h #internal
+`)
+	patterns := []klog.TagPattern{
+		klog.NewTagPatternOrPanic("client-*", ""),
+		klog.NewTagPatternOrPanic("ticket", "PROJ-*"),
+	}
+
+	t.Run("Without argument", func(t *testing.T) {
+		state, err := ctx._Run((&Tags{Patterns: patterns}).Run)
+		require.Nil(t, err)
+		assert.Equal(t, `
+#client-*      6h
+#ticket=PROJ-* 5h
+`, state.printBuffer)
+	})
+
+	t.Run("With values, count and untagged", func(t *testing.T) {
+		state, err := ctx._Run((&Tags{Patterns: patterns, Values: true, Count: true, WithUntagged: true}).Run)
+		require.Nil(t, err)
+		assert.Equal(t, `
+#client-*        6h     (3)
+ #client-acme       4h  (2)
+ #client-globex     2h  (1)
+ #client-initech    2h  (1)
+#ticket=PROJ-*   5h     (2)
+ #ticket=PROJ-1     3h  (1)
+ #ticket=PROJ-2     2h  (1)
+(untagged)       0m     (0)
+`, state.printBuffer)
+	})
+
+	t.Run("As JSON", func(t *testing.T) {
+		state, err := ctx._Run((&Tags{Patterns: patterns[1:], OutputArgs: args.OutputArgs{Output: "json"}}).Run)
+		require.Nil(t, err)
+		assert.Contains(t, state.printBuffer, `"patterns":[{"pattern":"#ticket=PROJ-*","total":"5h","total_mins":300,"count":2,"tags":[`+
+			`{"tag":"#ticket=PROJ-1","name":"ticket","value":"PROJ-1","total":"3h","total_mins":180,"count":1},`+
+			`{"tag":"#ticket=PROJ-2","name":"ticket","value":"PROJ-2","total":"2h","total_mins":120,"count":1}`+
+			`]}]`)
+	})
+}
@@ -19,11 +19,13 @@ func NewTestingContext() TestingContext {
 	return TestingContext{
 		State: State{
 			printBuffer:         "",
+			printErrBuffer:      "",
 			writtenFileContents: "",
 		},
 		now:            gotime.Now(),
 		records:        nil,
 		blocks:         nil,
+		rawInput:       "",
 		styler:         styler,
 		serialiser:     app.NewSerialiser(styler, false),
 		bookmarks:      bc,
@@ -47,6 +49,19 @@ func (ctx TestingContext) _SetRecords(recordsText string) TestingContext {
 	return ctx
 }
 
+func (ctx TestingContext) _SetRawInput(rawInput string) TestingContext {
+	ctx.rawInput = rawInput
+	return ctx
+}
+
+// _SetReloadAfterWrite makes subsequent invocations of `ReconcileFile` operate
+// on the updated file contents, like it would be the case with a real file.
+// This is needed for commands that reconcile the same file multiple times.
+func (ctx TestingContext) _SetReloadAfterWrite() TestingContext {
+	ctx.reloadAfterWrite = true
+	return ctx
+}
+
 func (ctx TestingContext) _SetNow(Y int, M int, D int, h int, m int) TestingContext {
 	ctx.now = gotime.Date(Y, gotime.Month(M), D, h, m, 0, 0, gotime.UTC)
 	return ctx
@@ -83,33 +98,40 @@ func (ctx TestingContext) _Run(cmd func(app.Context) app.Error) (State, app.Erro
 	if len(out) > 0 && out[0] != '\n' {
 		out = "\n" + out
 	}
-	return State{out, ctx.writtenFileContents}, cmdErr
+	return State{out, ctx.printErrBuffer, ctx.writtenFileContents}, cmdErr
 }
 
 type State struct {
 	printBuffer         string
+	printErrBuffer      string
 	writtenFileContents string
 }
 
 type TestingContext struct {
 	State
-	now            gotime.Time
-	records        []klog.Record
-	blocks         []txt.Block
-	styler         tf.Styler
-	serialiser     app.TextSerialiser
-	bookmarks      app.BookmarksCollection
-	editorsAuto    []shellcmd.Command
-	editorExplicit string
-	fileExplorers  []shellcmd.Command
-	execute        func(shellcmd.Command) app.Error
-	config         *app.Config
+	now              gotime.Time
+	records          []klog.Record
+	blocks           []txt.Block
+	rawInput         string
+	reloadAfterWrite bool
+	styler           tf.Styler
+	serialiser       app.TextSerialiser
+	bookmarks        app.BookmarksCollection
+	editorsAuto      []shellcmd.Command
+	editorExplicit   string
+	fileExplorers    []shellcmd.Command
+	execute          func(shellcmd.Command) app.Error
+	config           *app.Config
 }
 
 func (ctx *TestingContext) Print(s string) {
 	ctx.printBuffer += s
 }
 
+func (ctx *TestingContext) PrintErr(s string) {
+	ctx.printErrBuffer += s
+}
+
 func (ctx *TestingContext) ReadLine() (string, app.Error) {
 	return "", nil
 }
@@ -135,12 +157,19 @@ func (ctx *TestingContext) ReadInputs(_ ...app.FileOrBookmarkName) ([]klog.Recor
 	return ctx.records, nil
 }
 
+func (ctx *TestingContext) ReadRawInput(_ string) (string, app.Error) {
+	return ctx.rawInput, nil
+}
+
 func (ctx *TestingContext) ReconcileFile(_ app.FileOrBookmarkName, creators []reconciling.Creator, reconcile ...reconciling.Reconcile) (*reconciling.Result, app.Error) {
 	result, err := app.ApplyReconciler(ctx.records, ctx.blocks, creators, reconcile...)
 	if err != nil {
 		return nil, err
 	}
 	ctx.writtenFileContents = result.AllSerialised
+	if ctx.reloadAfterWrite {
+		ctx.records, ctx.blocks, _ = parser.NewSerialParser().Parse(result.AllSerialised)
+	}
 	return result, nil
 }
 
@@ -18,9 +18,35 @@ type Today struct {
 	args.DecimalArgs
 	args.WarnArgs
 	args.NoStyleArgs
+	args.OutputArgs
 	args.InputFilesArgs
 }
 
+type todayJsonView struct {
+	Current  todayCurrentJsonView `json:"current"`
+	Other    todayOtherJsonView   `json:"other"`
+	All      todayAllJsonView     `json:"all"`
+	Warnings []string             `json:"warnings"`
+}
+
+type todayCurrentJsonView struct {
+	Date         string `json:"date"`
+	IsYesterday  bool   `json:"is_yesterday"`
+	RecordsCount int    `json:"records_count"`
+	helper.TotalsView
+	EndTime *string `json:"end_time"`
+}
+
+type todayOtherJsonView struct {
+	RecordsCount int `json:"records_count"`
+	helper.TotalsView
+}
+
+type todayAllJsonView struct {
+	helper.TotalsView
+	EndTime *string `json:"end_time"`
+}
+
 func (opt *Today) Help() string {
 	return `
 Convenience command to get a brief overview (“check in”) of the current day.
@@ -31,6 +57,9 @@ When both '--now' and '--diff' are set, it also calculates the forecasted end-ti
 (I.e. when the difference between should and actual time would be 0.)
 
 Use the '--follow' flag to keep the shell open and display changes live.
+
+With '--output json', the result is printed as JSON object, which always contains the should-total and the difference.
+The forecasted end-time ('end_time') is only included if '--now' is set, otherwise it’s 'null'.
 `
 }
 
@@ -85,6 +114,39 @@ func handle(opt *Today, ctx app.Context) app.Error {
 	grandDiff := service.Diff(grandShouldTotal, grandTotal)
 	grandEndTime, _ := klog.NewTimeFromGo(now).Plus(klog.NewDuration(0, 0).Minus(grandDiff))
 
+	if opt.IsJson() {
+		currentDate := klog.NewDateFromGo(now)
+		if isYesterday {
+			currentDate = currentDate.PlusDays(-1)
+		}
+		endTime := func(t klog.Time) *string {
+			if !opt.Now || !hasCurrentRecords || t == nil {
+				return nil
+			}
+			s := t.ToString()
+			return &s
+		}
+		helper.PrintJson(ctx, todayJsonView{
+			Current: todayCurrentJsonView{
+				Date:         currentDate.ToString(),
+				IsYesterday:  isYesterday,
+				RecordsCount: len(currentRecords),
+				TotalsView:   helper.NewTotalsView(currentTotal, currentShouldTotal),
+				EndTime:      endTime(currentEndTime),
+			},
+			Other: todayOtherJsonView{
+				RecordsCount: len(otherRecords),
+				TotalsView:   helper.NewTotalsView(otherTotal, otherShouldTotal),
+			},
+			All: todayAllJsonView{
+				TotalsView: helper.NewTotalsView(grandTotal, grandShouldTotal),
+				EndTime:    endTime(grandEndTime),
+			},
+			Warnings: opt.WarnArgs.GatherWarnings(ctx, records, []service.UsageWarning{opt.NowArgs.GetWarning()}),
+		})
+		return nil
+	}
+
 	numberOfValueColumns := func() int {
 		if opt.Diff {
 			if opt.Now {
@@ -164,3 +164,36 @@ Other        6h50m    3h10m!   +3h40m
 All          6h50m    3h10m!   +3h40m        n/a
 `, state.printBuffer)
 }
+
+func TestPrintsEvaluationAsJson(t *testing.T) {
+	state, err := NewTestingContext()._SetNow(1999, 3, 14, 13, 30)._SetRecords(`
+1999-03-13 (8h!)
+	9h
+
+1999-03-14 (8h!)
+	9:00 - 12:00
+	13:00 - ?
+`)._Run((&Today{
+		NowArgs:    args.NowArgs{Now: true},
+		OutputArgs: args.OutputArgs{Output: "json"},
+	}).Run)
+	require.Nil(t, err)
+	assert.Equal(t, "\n"+`{`+
+		`"current":{"date":"1999-03-14","is_yesterday":false,"records_count":1,"total":"3h30m","total_mins":210,"should_total":"8h!","should_total_mins":480,"diff":"-4h30m","diff_mins":-270,"end_time":"18:00"},`+
+		`"other":{"records_count":1,"total":"9h","total_mins":540,"should_total":"8h!","should_total_mins":480,"diff":"+1h","diff_mins":60},`+
+		`"all":{"total":"12h30m","total_mins":750,"should_total":"16h!","should_total_mins":960,"diff":"-3h30m","diff_mins":-210,"end_time":"17:00"},`+
+		`"warnings":null}`+"\n", state.printBuffer)
+}
+
+func TestPrintsEvaluationAsJsonWithoutEndTime(t *testing.T) {
+	state, err := NewTestingContext()._SetNow(1999, 3, 14, 13, 30)._SetRecords(`
+1999-03-13 (8h!)
+	9h
+`)._Run((&Today{OutputArgs: args.OutputArgs{Output: "json"}}).Run)
+	require.Nil(t, err)
+	assert.Equal(t, "\n"+`{`+
+		`"current":{"date":"1999-03-13","is_yesterday":true,"records_count":1,"total":"9h","total_mins":540,"should_total":"8h!","should_total_mins":480,"diff":"+1h","diff_mins":60,"end_time":null},`+
+		`"other":{"records_count":0,"total":"0m","total_mins":0,"should_total":"0m!","should_total_mins":0,"diff":"0m","diff_mins":0},`+
+		`"all":{"total":"9h","total_mins":540,"should_total":"8h!","should_total_mins":480,"diff":"+1h","diff_mins":60,"end_time":null},`+
+		`"warnings":null}`+"\n", state.printBuffer)
+}
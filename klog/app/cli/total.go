@@ -5,6 +5,7 @@ import (
 
 	"github.com/jotaen/klog/klog/app"
 	"github.com/jotaen/klog/klog/app/cli/args"
+	"github.com/jotaen/klog/klog/app/cli/helper"
 	"github.com/jotaen/klog/klog/service"
 )
 
@@ -15,15 +16,24 @@ type Total struct {
 	args.DecimalArgs
 	args.WarnArgs
 	args.NoStyleArgs
+	args.OutputArgs
 	args.InputFilesArgs
 }
 
+type totalJsonView struct {
+	helper.TotalsView
+	RecordsCount int      `json:"records_count"`
+	Warnings     []string `json:"warnings"`
+}
+
 func (opt *Total) Help() string {
 	return `
 By default, the total time consists of all durations and time ranges, but it doesn’t include open-ended time ranges (e.g., '8:00 - ?').
 If you want to factor them in anyway, you can use the '--now' option, which treats all open-ended time ranges as if they were closed “right now”.
 
 If the records contain should-total values, you can also compute the difference between should-total and actual total by using the '--diff' flag.
+
+With '--output json', the result is printed as JSON object, which always contains the should-total and the difference.
 `
 }
 
@@ -36,7 +46,7 @@ func (opt *Total) Run(ctx app.Context) app.Error {
 		return err
 	}
 	now := ctx.Now()
-	records, fErr := opt.ApplyFilter(now, records)
+	records, fErr := opt.ApplyFilter(now, ctx.Config().NamedFilters.UnwrapOr(nil), records)
 	if fErr != nil {
 		return fErr
 	}
@@ -45,6 +55,15 @@ func (opt *Total) Run(ctx app.Context) app.Error {
 		return nErr
 	}
 	total := service.Total(records...)
+	warnings := []service.UsageWarning{opt.NowArgs.GetWarning(), opt.DiffArgs.GetWarning(opt.FilterArgs)}
+	if opt.IsJson() {
+		helper.PrintJson(ctx, totalJsonView{
+			TotalsView:   helper.NewTotalsView(total, service.ShouldTotalSum(records...)),
+			RecordsCount: len(records),
+			Warnings:     opt.WarnArgs.GatherWarnings(ctx, records, warnings),
+		})
+		return nil
+	}
 	ctx.Print(fmt.Sprintf("Total: %s\n", serialiser.Duration(total)))
 	if opt.Diff {
 		should := service.ShouldTotalSum(records...)
@@ -59,6 +78,6 @@ func (opt *Total) Run(ctx app.Context) app.Error {
 		return "s"
 	}()))
 
-	opt.WarnArgs.PrintWarnings(ctx, records, []service.UsageWarning{opt.NowArgs.GetWarning(), opt.DiffArgs.GetWarning(opt.FilterArgs)})
+	opt.WarnArgs.PrintWarnings(ctx, records, warnings)
 	return nil
 }
@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/jotaen/klog/klog/app/cli/args"
+	"github.com/jotaen/klog/klog/service/filter"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -42,6 +43,42 @@ func TestTotalWithDiffing(t *testing.T) {
 	assert.Equal(t, "\nTotal: 16h30m\nShould: 15h45m!\nDiff: +45m\n(In 2 records)\n", state.printBuffer)
 }
 
+func TestTotalWithRelativeDateFilter(t *testing.T) {
+	state, err := NewTestingContext()._SetRecords(`
+2018-11-07
+	1h
+
+2018-11-08
+	2h
+
+2018-11-09
+	4h
+`)._SetNow(2018, 11, 9, 12, 0)._Run((&Total{FilterArgs: args.FilterArgs{Filter: "yesterday...today"}}).Run)
+	require.Nil(t, err)
+	assert.Equal(t, "\nTotal: 6h\n(In 2 records)\n", state.printBuffer)
+}
+
+func TestTotalWithNamedFilter(t *testing.T) {
+	ctx := NewTestingContext()._SetRecords(`
+2018-11-07
+	1h #acme
+	30m #internal
+
+2018-11-08
+	2h #acme
+	4h #beta
+`)._SetFileConfig(`
+named_filters = billable: #acme || #beta
+`)
+	state, err := ctx._Run((&Total{FilterArgs: args.FilterArgs{Filter: "@billable && 2018-11-07"}}).Run)
+	require.Nil(t, err)
+	assert.Equal(t, "\nTotal: 1h\n(In 1 record)\n", state.printBuffer)
+
+	_, uErr := ctx._Run((&Total{FilterArgs: args.FilterArgs{Filter: "@unknown"}}).Run)
+	require.Error(t, uErr)
+	assert.ErrorIs(t, uErr.Original().(filter.ParseError).Original(), filter.ErrUnknownNamedFilter)
+}
+
 func TestTotalWithNow(t *testing.T) {
 	state, err := NewTestingContext()._SetRecords(`
 2018-11-08 (8h!)
@@ -73,3 +110,15 @@ func TestTotalAsDecimal(t *testing.T) {
 	require.Nil(t, err)
 	assert.Equal(t, "\nTotal: 510\n(In 1 record)\n", state.printBuffer)
 }
+
+func TestTotalAsJson(t *testing.T) {
+	state, err := NewTestingContext()._SetRecords(`
+2018-11-08 (8h!)
+	8h30m
+
+2018-11-09 (7h45m!)
+	8:00 - 16:00
+`)._Run((&Total{OutputArgs: args.OutputArgs{Output: "json"}}).Run)
+	require.Nil(t, err)
+	assert.Equal(t, "\n"+`{"total":"16h30m","total_mins":990,"should_total":"15h45m!","should_total_mins":945,"diff":"+45m","diff_mins":45,"records_count":2,"warnings":null}`+"\n", state.printBuffer)
+}
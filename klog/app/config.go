@@ -7,6 +7,7 @@ import (
 	"github.com/jotaen/genie"
 	"github.com/jotaen/klog/klog"
 	"github.com/jotaen/klog/klog/service"
+	"github.com/jotaen/klog/klog/service/filter"
 	tf "github.com/jotaen/klog/lib/terminalformat"
 )
 
@@ -46,6 +47,17 @@ type Config struct {
 	// NoWarnings indicates klog should suppress any warning types.
 	NoWarnings OptionalParam[service.DisabledCheckers]
 
+	// InvoiceRates are the hourly rates per tag for `klog invoice`.
+	InvoiceRates OptionalParam[[]service.Rate]
+
+	// OpeningBalance is the balance that the running balance in `klog report`
+	// starts from.
+	OpeningBalance OptionalParam[klog.Duration]
+
+	// NamedFilters are filter queries that can be referenced by name, e.g.
+	// in `--filter '@billable'`.
+	NamedFilters OptionalParam[filter.NamedFilters]
+
 	originalConfigFile genie.Data
 }
 
@@ -80,9 +92,14 @@ func NewConfig(numCpus int, getEnvVar func(string) string, configFileContents st
 		}
 		rErr := entry.read(value, &config)
 		if rErr != nil {
+			details := "The value for the `" + key + "` setting is not valid: " + entry.Help.Value
+			var ivErr invalidValueError
+			if errors.As(rErr, &ivErr) {
+				details += "\n" + ivErr.Error()
+			}
 			return Config{}, NewError(
 				"Invalid config file",
-				"The value for the `"+key+"` setting is not valid: "+entry.Help.Value,
+				details,
 				rErr,
 			)
 		}
@@ -111,9 +128,18 @@ func NewDefaultConfig(c tf.ColourTheme) Config {
 		DefaultRounding:    newOptionalParam[service.Rounding](),
 		DefaultShouldTotal: newOptionalParam[klog.ShouldTotal](),
 		NoWarnings:         newOptionalParam[service.DisabledCheckers](),
+		InvoiceRates:       newOptionalParam[[]service.Rate](),
+		OpeningBalance:     newOptionalParam[klog.Duration](),
+		NamedFilters:       newOptionalParam[filter.NamedFilters](),
 	}
 }
 
+// invalidValueError can be returned when reading a config file entry, if the
+// reason why the value is invalid is relevant to the user.
+type invalidValueError struct {
+	error
+}
+
 type Help struct {
 	Summary string
 	Default string
@@ -245,7 +271,8 @@ var CONFIG_FILE_ENTRIES = []ConfigFileEntries[any]{
 				"`OVERLAPPING_RANGES` (for time ranges that overlap), " +
 				"`MORE_THAN_24H` (if there is a record with more than 24h total), " +
 				"`POINTLESS_NOW` (when using --now without any open ranges), " +
-				"`ENTRY_FILTERED_DIFFING` (when combining --diff and entry-level filtering). " +
+				"`ENTRY_FILTERED_DIFFING` (when combining --diff and entry-level filtering), " +
+				"`SKIPPED_IMPORT` (when importing data that cannot be represented, e.g. calendar events that span several days). " +
 				"Multiple values must be separated by a comma, e.g.: `UNCLOSED_OPEN_RANGE, MORE_THAN_24H`.",
 			Default: "If absent/empty, klog prints all available warnings.",
 		},
@@ -265,6 +292,51 @@ var CONFIG_FILE_ENTRIES = []ConfigFileEntries[any]{
 			config.NoWarnings.set(disabledCheckers)
 			return nil
 		},
+	}, {
+		Name: "invoice_rates",
+		Help: Help{
+			Summary: "The hourly rates per tag that shall be used for billing time via `klog invoice`.",
+			Value:   "The config property must be a comma-separated list of tags, each followed by the amount per hour. Example: `#acme 120, #project=beta 95.50`.",
+			Default: "If absent/empty, `klog invoice` requires the rates to be specified via the `--rates` flag.",
+		},
+		read: func(value string, config *Config) error {
+			rates, err := service.NewRatesFromString(value)
+			if err != nil {
+				return err
+			}
+			config.InvoiceRates.set(rates)
+			return nil
+		},
+	}, {
+		Name: "opening_balance",
+		Help: Help{
+			Summary: "The opening balance of your overtime account, which the running balance starts from, e.g. in `klog report --balance`. You can use it to carry over a balance from before you started tracking your time with klog.",
+			Value:   "The config property must be a duration, optionally prefixed with a sign. Examples: `+12h30m`, `-3h`.",
+			Default: "If absent/empty, the running balance starts from 0.",
+		},
+		read: func(value string, config *Config) error {
+			d, err := klog.NewDurationFromString(value)
+			if err != nil {
+				return err
+			}
+			config.OpeningBalance.set(d)
+			return nil
+		},
+	}, {
+		Name: "named_filters",
+		Help: Help{
+			Summary: "Filter queries that shall be available by name, so that you can reference them in filter expressions, e.g. `klog total --filter '@billable && 2024-Q1'`.",
+			Value:   "The config property must be a semicolon-separated list of names, each followed by a colon and a filter query. A name may consist of letters, digits, `_` and `-`. The filter queries cannot reference other named filters. Example: `billable: #client-* && !#internal; weekend: weekday:sat...sun`.",
+			Default: "If absent/empty, there are no named filters.",
+		},
+		read: func(value string, config *Config) error {
+			namedFilters, err := filter.NewNamedFiltersFromString(value)
+			if err != nil {
+				return invalidValueError{err}
+			}
+			config.NamedFilters.set(namedFilters)
+			return nil
+		},
 	},
 }
 
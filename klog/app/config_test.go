@@ -5,6 +5,7 @@ import (
 
 	"github.com/jotaen/klog/klog"
 	"github.com/jotaen/klog/klog/service"
+	"github.com/jotaen/klog/klog/service/filter"
 	tf "github.com/jotaen/klog/lib/terminalformat"
 	"github.com/stretchr/testify/assert"
 )
@@ -243,6 +244,85 @@ func TestNoWarningsParamFromConfigFile(t *testing.T) {
 	}
 }
 
+func TestSetsInvoiceRatesParamFromConfigFile(t *testing.T) {
+	c, err := NewConfig(
+		1,
+		createMockConfigFromEnv(map[string]string{}),
+		`invoice_rates = #acme 120, #project=beta 95.50`,
+	)
+	assert.Nil(t, err)
+	var value []service.Rate
+	c.InvoiceRates.Unwrap(func(rs []service.Rate) {
+		value = rs
+	})
+	assert.Equal(t, []service.Rate{
+		{Tag: klog.NewTagOrPanic("acme", ""), PerHour: 12000},
+		{Tag: klog.NewTagOrPanic("project", "beta"), PerHour: 9550},
+	}, value)
+
+	_, iErr := NewConfig(
+		1,
+		createMockConfigFromEnv(map[string]string{}),
+		`invoice_rates = acme 120`,
+	)
+	assert.Error(t, iErr)
+}
+
+func TestSetsOpeningBalanceParamFromConfigFile(t *testing.T) {
+	for _, x := range []struct {
+		cfg string
+		exp klog.Duration
+	}{
+		{`opening_balance = 12h30m`, klog.NewDuration(12, 30)},
+		{`opening_balance = +1h`, klog.NewDuration(1, 0)},
+		{`opening_balance = -3h15m`, klog.NewDuration(-3, -15)},
+	} {
+		c, err := NewConfig(
+			1,
+			createMockConfigFromEnv(map[string]string{}),
+			x.cfg,
+		)
+		assert.Nil(t, err)
+		var value klog.Duration
+		c.OpeningBalance.Unwrap(func(d klog.Duration) {
+			value = d
+		})
+		assert.Equal(t, x.exp.InMinutes(), value.InMinutes())
+	}
+
+	_, iErr := NewConfig(
+		1,
+		createMockConfigFromEnv(map[string]string{}),
+		`opening_balance = 3 hours`,
+	)
+	assert.Error(t, iErr)
+}
+
+func TestSetsNamedFiltersParamFromConfigFile(t *testing.T) {
+	c, err := NewConfig(
+		1,
+		createMockConfigFromEnv(map[string]string{}),
+		`named_filters = billable: #client-* && !#internal; weekend: weekday:sat...sun`,
+	)
+	assert.Nil(t, err)
+	var value filter.NamedFilters
+	c.NamedFilters.Unwrap(func(nfs filter.NamedFilters) {
+		value = nfs
+	})
+	assert.Equal(t, filter.NamedFilters{
+		"billable": "#client-* && !#internal",
+		"weekend":  "weekday:sat...sun",
+	}, value)
+
+	_, iErr := NewConfig(
+		1,
+		createMockConfigFromEnv(map[string]string{}),
+		`named_filters = billable: #client-* && foo`,
+	)
+	assert.Error(t, iErr)
+	assert.Contains(t, iErr.Details(), "Invalid named filter `billable`")
+}
+
 func TestSerialisesConfigFile(t *testing.T) {
 	for _, tml := range []string{`
 editor = 
@@ -252,6 +332,9 @@ default_should_total =
 date_format = 
 time_convention = 
 no_warnings = 
+invoice_rates = 
+opening_balance = 
+named_filters = 
 `, `
 editor = 
 colour_scheme = light
@@ -260,6 +343,9 @@ default_should_total =
 date_format = YYYY/MM/DD
 time_convention = 
 no_warnings = FUTURE_ENTRIES
+invoice_rates = 
+opening_balance = 
+named_filters = 
 `, `
 editor = subl
 colour_scheme = dark
@@ -268,6 +354,9 @@ default_should_total = 8h!
 date_format = YYYY-MM-DD
 time_convention = 24h
 no_warnings = MORE_THAN_24H, OVERLAPPING_RANGES
+invoice_rates = #acme 120, #project=beta 95.50
+opening_balance = -3h15m
+named_filters = billable: #client-* && !#internal; weekend: weekday:sat...sun
 `} {
 		cfg, _ := NewConfig(
 			1,
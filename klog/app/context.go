@@ -35,6 +35,9 @@ type Context interface {
 	// Print prints to stdout.
 	Print(string)
 
+	// PrintErr prints to stderr.
+	PrintErr(string)
+
 	// ReadLine reads user input from stdin.
 	ReadLine() (string, Error)
 
@@ -47,6 +50,10 @@ type Context interface {
 	// ReadInputs retrieves all input from the given file or bookmark names.
 	ReadInputs(...FileOrBookmarkName) ([]klog.Record, Error)
 
+	// ReadRawInput retrieves the unprocessed contents of a file, or of stdin if
+	// no file path was specified.
+	ReadRawInput(string) (string, Error)
+
 	// RetrieveTargetFile returns the desired file, requiring that there is exactly one.
 	RetrieveTargetFile(fileArg FileOrBookmarkName) (FileWithContents, Error)
 
@@ -132,6 +139,10 @@ func (ctx *context) Print(text string) {
 	fmt.Print(text)
 }
 
+func (ctx *context) PrintErr(text string) {
+	fmt.Fprint(os.Stderr, text)
+}
+
 func (ctx *context) ReadLine() (string, Error) {
 	scanner := bufio.NewScanner(os.Stdin)
 	if scanner.Scan() {
@@ -192,6 +203,29 @@ func (ctx *context) ReadInputs(fileArgs ...FileOrBookmarkName) ([]klog.Record, E
 	return allRecords, nil
 }
 
+func (ctx *context) ReadRawInput(path string) (string, Error) {
+	if path == "" {
+		stdin, err := ReadStdin()
+		if err != nil {
+			return "", err
+		}
+		if stdin == "" {
+			return "", NewErrorWithCode(
+				NO_INPUT_ERROR,
+				"No input given",
+				"Please either specify a file or pipe the data via stdin",
+				nil,
+			)
+		}
+		return stdin, nil
+	}
+	file, err := NewFile(path)
+	if err != nil {
+		return "", err
+	}
+	return ReadFile(file)
+}
+
 func (ctx *context) RetrieveTargetFile(fileArg FileOrBookmarkName) (FileWithContents, Error) {
 	bc, err := ctx.ReadBookmarks()
 	if err != nil {
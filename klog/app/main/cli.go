@@ -20,13 +20,21 @@ import (
 )
 
 func Run(homeDir app.File, meta app.Meta, config app.Config, args []string) (int, error) {
+	styler := tf.NewStyler(config.ColourScheme.Value())
+	ctx := app.NewContext(
+		homeDir,
+		meta,
+		styler,
+		config,
+	)
+
 	kongApp, nErr := kong.New(
 		&cli.Cli{},
 		kong.Name("klog"),
 		kong.Description((&cli.Default{}).Help()),
 		func() kong.Option {
 			datePrototype, _ := klog.NewDate(1, 1, 1)
-			return kong.TypeMapper(reflect.TypeOf(&datePrototype).Elem(), dateDecoder())
+			return kong.TypeMapper(reflect.TypeOf(&datePrototype).Elem(), dateDecoder(ctx))
 		}(),
 		func() kong.Option {
 			timePrototype, _ := klog.NewTime(0, 0)
@@ -47,8 +55,8 @@ func Run(homeDir app.File, meta app.Meta, config app.Config, args []string) (int
 			return kong.TypeMapper(reflect.TypeOf(&f).Elem(), roundingDecoder())
 		}(),
 		func() kong.Option {
-			t := klog.NewTagOrPanic("test", "")
-			return kong.TypeMapper(reflect.TypeOf(&t).Elem(), tagDecoder())
+			t := klog.NewTagPatternOrPanic("test", "")
+			return kong.TypeMapper(reflect.TypeOf(&t).Elem(), tagPatternDecoder())
 		}(),
 		func() kong.Option {
 			s, _ := klog.NewRecordSummary("test")
@@ -70,14 +78,6 @@ func Run(homeDir app.File, meta app.Meta, config app.Config, args []string) (int
 		return app.GENERAL_ERROR.ToInt(), errors.New("Internal error: " + nErr.Error())
 	}
 
-	styler := tf.NewStyler(config.ColourScheme.Value())
-	ctx := app.NewContext(
-		homeDir,
-		meta,
-		styler,
-		config,
-	)
-
 	// When klog is invoked by shell completion (specifically, when the
 	// bash-specific COMP_LINE environment variable is set), the
 	// kongplete.Complete function generates a list of possible completions,
@@ -184,6 +184,63 @@ func TestDecodesDate(t *testing.T) {
 	)
 }
 
+func TestDecodesRelativeDate(t *testing.T) {
+	(&Env{
+		files: map[string]string{
+			"test.klg": "2020-01-01\nSome stuff\n\t1h7m\n\n9999-12-31\nFuture stuff\n\t2h\n",
+		},
+	}).execute(t,
+		invocation{
+			args: []string{"total", "--since", "-1d", "test.klg"},
+			test: func(t *testing.T, code int, out string) {
+				assert.Equal(t, 0, code)
+				assert.True(t, strings.Contains(out, "2h"), out)
+				assert.True(t, strings.Contains(out, "1 record"), out)
+			}},
+		invocation{
+			args: []string{"total", "--until=yesterday", "test.klg"},
+			test: func(t *testing.T, code int, out string) {
+				assert.Equal(t, 0, code)
+				assert.True(t, strings.Contains(out, "1h7m"), out)
+				assert.True(t, strings.Contains(out, "1 record"), out)
+			}},
+		invocation{
+			args: []string{"total", "--since", "-2x", "test.klg"},
+			test: func(t *testing.T, code int, out string) {
+				assert.Equal(t, 1, code)
+			}},
+	)
+}
+
+func TestPrintsErrorsOfContextDependentFlags(t *testing.T) {
+	(&Env{
+		files: map[string]string{
+			"test.klg": "9999-12-31\n\t8:00 - ?\n",
+		},
+	}).execute(t,
+		invocation{
+			args: []string{"total", "--now", "--since", "-1d", "test.klg"},
+			test: func(t *testing.T, code int, out string) {
+				assert.Equal(t, 8, code)
+				assert.True(t, strings.Contains(out, "Error: Cannot apply --now flag"), out)
+				assert.True(t, strings.Contains(out, "There are records with uncloseable time ranges"), out)
+			}},
+		invocation{
+			args: []string{"total", "--since", "-1d", "@unknown"},
+			test: func(t *testing.T, code int, out string) {
+				assert.Equal(t, 4, code)
+				assert.True(t, strings.Contains(out, "Error: Cannot retrieve files"), out)
+				assert.True(t, strings.Contains(out, "No such bookmark: @unknown"), out)
+			}},
+		invocation{
+			args: []string{"total", "--since", "-1d", "--filter", "@billable", "test.klg"},
+			test: func(t *testing.T, code int, out string) {
+				assert.Equal(t, 1, code)
+				assert.True(t, strings.Contains(out, "Unknown named filter"), out)
+			}},
+	)
+}
+
 func TestDecodesTime(t *testing.T) {
 	(&Env{
 		files: map[string]string{
@@ -334,6 +391,20 @@ func TestDecodesTags(t *testing.T) {
 				assert.Equal(t, 0, code)
 				assert.True(t, strings.Contains(out, "#bar=1"), out)
 			}},
+		invocation{
+			args: []string{"print", "--tag", "f*", "test.klg"},
+			test: func(t *testing.T, code int, out string) {
+				assert.Equal(t, 0, code)
+				assert.True(t, strings.Contains(out, "#foo"), out)
+				assert.False(t, strings.Contains(out, "#bar"), out)
+			}},
+		invocation{
+			args: []string{"print", "--tag", "*=*", "test.klg"},
+			test: func(t *testing.T, code int, out string) {
+				assert.Equal(t, 0, code)
+				assert.False(t, strings.Contains(out, "#foo"), out)
+				assert.True(t, strings.Contains(out, "#bar=1"), out)
+			}},
 	)
 }
 
@@ -2,6 +2,7 @@ package klog
 
 import (
 	"github.com/jotaen/klog/klog/app"
+	"github.com/jotaen/klog/klog/service/filter"
 	"github.com/posener/complete"
 )
 
@@ -20,10 +21,24 @@ func predictBookmarks(ctx app.Context) complete.Predictor {
 	return complete.PredictFunc(func(a complete.Args) []string { return thunk() })
 }
 
+func predictNamedFilters(ctx app.Context) complete.Predictor {
+	thunk := func() []string {
+		names := make([]string, 0)
+		ctx.Config().NamedFilters.Unwrap(func(nfs filter.NamedFilters) {
+			for _, name := range nfs.Names() {
+				names = append(names, "@"+name)
+			}
+		})
+		return names
+	}
+	return complete.PredictFunc(func(a complete.Args) []string { return thunk() })
+}
+
 func CompletionPredictors(ctx app.Context) map[string]complete.Predictor {
 	return map[string]complete.Predictor{
 		"file":             complete.PredictFiles("*.klg"),
 		"bookmark":         predictBookmarks(ctx),
 		"file_or_bookmark": complete.PredictOr(complete.PredictFiles("*.klg"), predictBookmarks(ctx)),
+		"named_filter":     predictNamedFilters(ctx),
 	}
 }
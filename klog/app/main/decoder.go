@@ -7,20 +7,31 @@ import (
 
 	"github.com/alecthomas/kong"
 	"github.com/jotaen/klog/klog"
+	"github.com/jotaen/klog/klog/app"
 	"github.com/jotaen/klog/klog/service"
 	"github.com/jotaen/klog/klog/service/period"
 )
 
-func dateDecoder() kong.MapperFunc {
+func dateDecoder(appCtx app.Context) kong.MapperFunc {
 	return func(ctx *kong.DecodeContext, target reflect.Value) error {
+		today := klog.NewDateFromGo(appCtx.Now())
 		var value string
-		if err := ctx.Scan.PopValueInto("date", &value); err != nil {
-			return err
+		// Relative dates such as `-14d` would be treated like a flag, so we
+		// have to consume them manually.
+		if next, isString := ctx.Scan.Peek().Value.(string); isString && strings.HasPrefix(next, "-") {
+			if _, rErr := period.NewDateFromRelativeString(next, today); rErr == nil {
+				value = ctx.Scan.Pop().Value.(string)
+			}
+		}
+		if value == "" {
+			if err := ctx.Scan.PopValueInto("date", &value); err != nil {
+				return err
+			}
 		}
 		if value == "" {
 			return errors.New("Please provide a valid date")
 		}
-		d, err := klog.NewDateFromString(value)
+		d, err := period.NewDateFromAbsoluteOrRelativeString(value, today)
 		if err != nil {
 			return errors.New("`" + value + "` is not a valid date")
 		}
@@ -103,7 +114,7 @@ func roundingDecoder() kong.MapperFunc {
 	}
 }
 
-func tagDecoder() kong.MapperFunc {
+func tagPatternDecoder() kong.MapperFunc {
 	return func(ctx *kong.DecodeContext, target reflect.Value) error {
 		var value string
 		if err := ctx.Scan.PopValueInto("tag", &value); err != nil {
@@ -112,7 +123,7 @@ func tagDecoder() kong.MapperFunc {
 		if value == "" {
 			return errors.New("Please provide a valid tag")
 		}
-		t, err := klog.NewTagFromString(value)
+		t, err := klog.NewTagPatternFromString(value)
 		if err != nil {
 			return errors.New("`" + value + "` is not a valid tag")
 		}
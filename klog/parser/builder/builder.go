@@ -0,0 +1,189 @@
+/*
+Package builder contains helpers for constructing records from the data of
+other sources, such as calendar events or intervals from other time-tracking
+tools.
+*/
+package builder
+
+import (
+	"errors"
+	"regexp"
+	"sort"
+	"strings"
+	gotime "time"
+
+	"github.com/jotaen/klog/klog"
+)
+
+// RecordBuilder collects entries and groups them into records by date.
+type RecordBuilder struct {
+	records map[string]klog.Record
+}
+
+func NewRecordBuilder() *RecordBuilder {
+	return &RecordBuilder{
+		records: make(map[string]klog.Record),
+	}
+}
+
+// AddRange adds a range entry to the record at the start date. The end may
+// be on the start date or on the day after. It returns an error if the
+// interval cannot be represented as range.
+func (b *RecordBuilder) AddRange(start gotime.Time, end gotime.Time, summary klog.EntrySummary) error {
+	return b.AddRangeAt(klog.NewDateFromGo(start), start, end, summary)
+}
+
+// AddRangeAt adds a range entry to the record at the given date. The start
+// and the end may be on the day before, on the date itself, or on the day
+// after, which yields shifted times accordingly. It returns an error if the
+// interval cannot be represented as range.
+func (b *RecordBuilder) AddRangeAt(date klog.Date, start gotime.Time, end gotime.Time, summary klog.EntrySummary) error {
+	if end.Before(start) {
+		return errors.New("Start and end time must be in chronological order")
+	}
+	startTime, err := toTime(date, start)
+	if err != nil {
+		return err
+	}
+	endTime, eErr := toTime(date, end)
+	if eErr != nil {
+		return eErr
+	}
+	tr, rErr := klog.NewRange(startTime, endTime)
+	if rErr != nil {
+		return errors.New("Start and end time must be in chronological order")
+	}
+	b.at(date).AddRange(tr, summary)
+	return nil
+}
+
+// AddOpenRange adds an open range entry to the record at the start date.
+// It returns an error if that record already contains an open range.
+func (b *RecordBuilder) AddOpenRange(start gotime.Time, summary klog.EntrySummary) error {
+	return b.AddOpenRangeAt(klog.NewDateFromGo(start), start, summary)
+}
+
+// AddOpenRangeAt adds an open range entry to the record at the given date.
+// The start may be on the day before, on the date itself, or on the day after.
+// It returns an error if that record already contains an open range.
+func (b *RecordBuilder) AddOpenRangeAt(date klog.Date, start gotime.Time, summary klog.EntrySummary) error {
+	startTime, err := toTime(date, start)
+	if err != nil {
+		return err
+	}
+	sErr := b.at(date).Start(klog.NewOpenRange(startTime), summary)
+	if sErr != nil {
+		return errors.New("There can only be one open range per record")
+	}
+	return nil
+}
+
+// AddDuration adds a duration entry to the record at the given date.
+func (b *RecordBuilder) AddDuration(date klog.Date, duration klog.Duration, summary klog.EntrySummary) {
+	b.at(date).AddDuration(duration, summary)
+}
+
+// Records returns all records, sorted by date (oldest first).
+func (b *RecordBuilder) Records() []klog.Record {
+	var result []klog.Record
+	for _, r := range b.records {
+		result = append(result, r)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return !result[i].Date().IsAfterOrEqual(result[j].Date())
+	})
+	return result
+}
+
+func (b *RecordBuilder) at(date klog.Date) klog.Record {
+	// Normalise the date, so that it’s independent of the original notation.
+	date = klog.NewDateFromGo(gotime.Date(date.Year(), gotime.Month(date.Month()), date.Day(), 0, 0, 0, 0, gotime.UTC))
+	key := date.ToString()
+	r, exists := b.records[key]
+	if !exists {
+		r = klog.NewRecord(date)
+		b.records[key] = r
+	}
+	return r
+}
+
+// toTime converts the time of day to a time relative to the date, i.e. it
+// yields a shifted time if the day is before or after the date.
+func toTime(date klog.Date, t gotime.Time) (klog.Time, error) {
+	day := gotime.Date(date.Year(), gotime.Month(date.Month()), date.Day(), 0, 0, 0, 0, gotime.UTC)
+	switch daysBetween(day, t) {
+	case -1:
+		return klog.NewTimeYesterday(t.Hour(), t.Minute())
+	case 0:
+		return klog.NewTime(t.Hour(), t.Minute())
+	case 1:
+		return klog.NewTimeTomorrow(t.Hour(), t.Minute())
+	}
+	return nil, errors.New("The time span must not exceed two days")
+}
+
+func daysBetween(start gotime.Time, end gotime.Time) int {
+	startDay := gotime.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, gotime.UTC)
+	endDay := gotime.Date(end.Year(), end.Month(), end.Day(), 0, 0, 0, 0, gotime.UTC)
+	return int(endDay.Sub(startDay).Hours() / 24)
+}
+
+var invalidTagNameChars = regexp.MustCompile(`[^\p{L}\d_/-]+`)
+
+// NewTag converts an arbitrary text (such as a category or a label) to a tag.
+// Characters that are not allowed in tag names are replaced. Texts in the form
+// of `name=value` yield tags with value, and texts in the form of `a/b` yield
+// hierarchical tags. It returns an error if no tag can be derived from the text.
+func NewTag(text string) (klog.Tag, error) {
+	name, value, _ := strings.Cut(strings.TrimSpace(strings.TrimPrefix(text, "#")), "=")
+	var segments []string
+	for _, s := range strings.Split(invalidTagNameChars.ReplaceAllString(name, "_"), klog.TagSeparator) {
+		// Empty segments are dropped, which collapses redundant separators.
+		if s = strings.Trim(s, "_"); s != "" {
+			segments = append(segments, s)
+		}
+	}
+	name = strings.Join(segments, klog.TagSeparator)
+	if name == "" {
+		return klog.Tag{}, errors.New("Invalid tag: `" + text + "`")
+	}
+	if value == "" {
+		return klog.NewTagFromString(name)
+	}
+	if strings.Contains(value, `"`) && strings.Contains(value, `'`) {
+		value = strings.ReplaceAll(value, `"`, `'`)
+	}
+	quote := `"`
+	if strings.Contains(value, `"`) {
+		quote = `'`
+	}
+	tag, err := klog.NewTagFromString(name + "=" + quote + value + quote)
+	if err != nil {
+		return klog.Tag{}, errors.New("Invalid tag: `" + text + "`")
+	}
+	return tag, nil
+}
+
+// NewSummary constructs an entry summary from a (free-form) text, and amends
+// it by the given tags. Line breaks and redundant whitespace in the text are
+// collapsed. Tags that already appear in the text are not repeated.
+func NewSummary(text string, tags []klog.Tag) (klog.EntrySummary, error) {
+	text = strings.Join(strings.Fields(text), " ")
+	title, err := klog.NewEntrySummary(text)
+	if err != nil {
+		return nil, err
+	}
+	for _, tag := range tags {
+		if title.Tags().Contains(tag) {
+			continue
+		}
+		if text != "" {
+			text += " "
+		}
+		text += tag.ToString()
+	}
+	if text == "" {
+		return nil, nil
+	}
+	return klog.NewEntrySummary(text)
+}
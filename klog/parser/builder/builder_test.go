@@ -0,0 +1,109 @@
+package builder
+
+import (
+	"testing"
+	gotime "time"
+
+	"github.com/jotaen/klog/klog"
+	"github.com/jotaen/klog/klog/parser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func at(day int, hour int, minute int) gotime.Time {
+	return gotime.Date(2024, 1, day, hour, minute, 0, 0, gotime.UTC)
+}
+
+func entryValue(e klog.Entry) string {
+	return klog.Unbox(&e,
+		func(tr klog.Range) string { return tr.ToString() },
+		func(d klog.Duration) string { return d.ToString() },
+		func(o klog.OpenRange) string { return o.ToString() },
+	)
+}
+
+func TestBuildsRecordsGroupedByDate(t *testing.T) {
+	b := NewRecordBuilder()
+	require.Nil(t, b.AddRange(at(2, 9, 0), at(2, 10, 30), nil))
+	require.Nil(t, b.AddRange(at(1, 23, 0), at(2, 1, 0), klog.Ɀ_EntrySummary_("Night")))
+	require.Nil(t, b.AddOpenRange(at(2, 11, 0), nil))
+	b.AddDuration(klog.Ɀ_Date_(2024, 1, 3), klog.NewDuration(1, 0), nil)
+
+	rs := b.Records()
+	require.Len(t, rs, 3)
+	assert.True(t, rs[0].Date().IsEqualTo(klog.Ɀ_Date_(2024, 1, 1)))
+	assert.Equal(t, "23:00 - 1:00>", entryValue(rs[0].Entries()[0]))
+	assert.Equal(t, "Night", parser.SummaryText(rs[0].Entries()[0].Summary()).ToString())
+	assert.True(t, rs[1].Date().IsEqualTo(klog.Ɀ_Date_(2024, 1, 2)))
+	assert.Equal(t, "9:00 - 10:30", entryValue(rs[1].Entries()[0]))
+	assert.Equal(t, "11:00 - ?", entryValue(rs[1].Entries()[1]))
+	assert.True(t, rs[2].Date().IsEqualTo(klog.Ɀ_Date_(2024, 1, 3)))
+	assert.Equal(t, "1h", entryValue(rs[2].Entries()[0]))
+}
+
+func TestBuildsShiftedEntriesAtGivenDate(t *testing.T) {
+	b := NewRecordBuilder()
+	date := klog.Ɀ_Date_(2024, 1, 2)
+	require.Nil(t, b.AddRangeAt(date, at(1, 23, 0), at(2, 1, 0), nil))
+	require.Nil(t, b.AddRangeAt(date, at(2, 22, 0), at(3, 0, 30), nil))
+	require.Nil(t, b.AddOpenRangeAt(date, at(1, 23, 30), nil))
+
+	rs := b.Records()
+	require.Len(t, rs, 1)
+	assert.True(t, rs[0].Date().IsEqualTo(date))
+	assert.Equal(t, "<23:00 - 1:00", entryValue(rs[0].Entries()[0]))
+	assert.Equal(t, "22:00 - 0:30>", entryValue(rs[0].Entries()[1]))
+	assert.Equal(t, "<23:30 - ?", entryValue(rs[0].Entries()[2]))
+
+	assert.Error(t, b.AddRangeAt(date, at(2, 9, 0), at(4, 9, 0), nil))
+	assert.Error(t, b.AddOpenRangeAt(date, at(4, 9, 0), nil))
+}
+
+func TestRejectsUnrepresentableEntries(t *testing.T) {
+	b := NewRecordBuilder()
+	assert.Error(t, b.AddRange(at(1, 9, 0), at(3, 9, 0), nil))
+	assert.Error(t, b.AddRange(at(1, 9, 0), at(1, 8, 0), nil))
+	require.Nil(t, b.AddOpenRange(at(1, 9, 0), nil))
+	assert.Error(t, b.AddOpenRange(at(1, 10, 0), nil))
+}
+
+func TestConvertsTextToTag(t *testing.T) {
+	for _, x := range []struct {
+		text   string
+		expect string
+	}{
+		{"foo", "#foo"},
+		{"#Foo", "#foo"},
+		{"Project X", "#project_x"},
+		{" (Sales) & Marketing ", "#sales_marketing"},
+		{"ticket=123", "#ticket=123"},
+		{"client=ACME Corp", `#client="ACME Corp"`},
+		{`quote=say "hi"`, `#quote='say "hi"'`},
+		{`quote=it's "hi"`, `#quote="it's 'hi'"`},
+		{"#acme/backend", "#acme/backend"},
+		{"/Acme//Back End/", "#acme/back_end"},
+		{"acme/ _ /backend=1", "#acme/backend=1"},
+	} {
+		tag, err := NewTag(x.text)
+		require.Nil(t, err, x.text)
+		assert.Equal(t, x.expect, tag.ToString())
+	}
+	for _, text := range []string{"", "  ", "%&/", "/", "=value"} {
+		_, err := NewTag(text)
+		assert.Error(t, err, text)
+	}
+}
+
+func TestConstructsSummary(t *testing.T) {
+	s, err := NewSummary("Hello\n  world #foo", []klog.Tag{klog.NewTagOrPanic("foo", ""), klog.NewTagOrPanic("bar", "1")})
+	require.Nil(t, err)
+	assert.Equal(t, "Hello world #foo #bar=1", parser.SummaryText(s).ToString())
+
+	s, err = NewSummary("", []klog.Tag{klog.NewTagOrPanic("foo", "")})
+	require.Nil(t, err)
+	assert.Equal(t, "#foo", parser.SummaryText(s).ToString())
+
+	s, err = NewSummary("", nil)
+	require.Nil(t, err)
+	assert.Nil(t, s)
+}
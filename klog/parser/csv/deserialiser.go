@@ -0,0 +1,320 @@
+package csv
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	gotime "time"
+
+	"github.com/jotaen/klog/klog"
+	"github.com/jotaen/klog/klog/parser/builder"
+)
+
+// The fields that can be read from CSV data.
+const (
+	FIELD_DATE          = "date"
+	FIELD_START         = "start"
+	FIELD_END           = "end"
+	FIELD_END_DATE      = "end_date"
+	FIELD_DURATION      = "duration"
+	FIELD_DURATION_MINS = "duration_mins"
+	FIELD_DESCRIPTION   = "description"
+	FIELD_TAGS          = "tags"
+	FIELD_PROJECT       = "project"
+)
+
+// Fields is the list of all fields that can be read from CSV data.
+var Fields = []string{
+	FIELD_DATE, FIELD_START, FIELD_END, FIELD_END_DATE, FIELD_DURATION,
+	FIELD_DURATION_MINS, FIELD_DESCRIPTION, FIELD_TAGS, FIELD_PROJECT,
+}
+
+// defaultColumns maps the fields to the column names that are commonly used
+// for them, e.g. in the CSV exports of other time-tracking tools, or in the
+// CSV export of klog itself. The column names are compared case-insensitively.
+var defaultColumns = map[string][]string{
+	FIELD_DATE:          {"date", "start date"},
+	FIELD_START:         {"start", "start time"},
+	FIELD_END:           {"end", "end time"},
+	FIELD_END_DATE:      {"end date"},
+	FIELD_DURATION:      {"duration", "duration (h)", "duration (decimal)"},
+	FIELD_DURATION_MINS: {"duration_mins"},
+	FIELD_DESCRIPTION:   {"description", "entry_summary"},
+	FIELD_TAGS:          {"tags"},
+	FIELD_PROJECT:       {"project"},
+}
+
+// ReadOptions controls how CSV data is read.
+type ReadOptions struct {
+	// Delimiter is the character that separates the fields of a row.
+	Delimiter rune
+
+	// Columns maps fields to the names of the columns in the header row.
+	// For all fields that are absent in the map, the default column names
+	// are used.
+	Columns map[string]string
+
+	// DateFormat is the format of the dates, where `YYYY` stands for the year,
+	// `MM` for the month and `DD` for the day, e.g. `DD.MM.YYYY`. If empty,
+	// the dates must be in klog’s notation.
+	DateFormat string
+}
+
+// FromCsv reads CSV data and converts the rows to records. The first row of
+// the data must be a header row with the column names. Every subsequent row
+// yields one entry in the record at the row’s date:
+//   - If there is a start and end time, it yields a range. If the end time is
+//     before the start time, the end is assumed to be on the next day (unless
+//     there is an explicit end date).
+//   - If there is only a start time, it yields an open range.
+//   - If there is only a duration, it yields a duration entry.
+//
+// The description becomes the entry summary, and the tags and the project
+// become tags. The resulting records are sorted by date.
+func FromCsv(text string, opts ReadOptions) ([]klog.Record, error) {
+	r := csv.NewReader(strings.NewReader(text))
+	r.Comma = opts.Delimiter
+	r.FieldsPerRecord = -1
+	header, err := r.Read()
+	if err == io.EOF {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.New("Malformed CSV: " + err.Error())
+	}
+	columns, cErr := resolveColumns(header, opts.Columns)
+	if cErr != nil {
+		return nil, cErr
+	}
+	if _, hasDate := columns[FIELD_DATE]; !hasDate {
+		return nil, errors.New("There is no column for the date")
+	}
+	parseDate, dErr := newDateParser(opts.DateFormat)
+	if dErr != nil {
+		return nil, dErr
+	}
+
+	b := builder.NewRecordBuilder()
+	for {
+		row, rErr := r.Read()
+		if rErr == io.EOF {
+			break
+		}
+		if rErr != nil {
+			return nil, errors.New("Malformed CSV: " + rErr.Error())
+		}
+		line, _ := r.FieldPos(0)
+		value := func(field string) string {
+			i, ok := columns[field]
+			if !ok || i >= len(row) {
+				return ""
+			}
+			return strings.TrimSpace(row[i])
+		}
+		if strings.TrimSpace(strings.Join(row, "")) == "" {
+			continue
+		}
+		aErr := addRow(b, parseDate, value)
+		if aErr != nil {
+			return nil, fmt.Errorf("Line %d: %w", line, aErr)
+		}
+	}
+	return b.Records(), nil
+}
+
+func resolveColumns(header []string, mapping map[string]string) (map[string]int, error) {
+	indexOf := func(name string) int {
+		for i, h := range header {
+			if strings.EqualFold(strings.TrimSpace(h), strings.TrimSpace(name)) {
+				return i
+			}
+		}
+		return -1
+	}
+	result := make(map[string]int)
+	for field, name := range mapping {
+		if _, isKnown := defaultColumns[field]; !isKnown {
+			return nil, errors.New("Unknown field `" + field + "`")
+		}
+		i := indexOf(name)
+		if i == -1 {
+			return nil, errors.New("There is no column `" + name + "`")
+		}
+		result[field] = i
+	}
+	for field, names := range defaultColumns {
+		if _, isMapped := mapping[field]; isMapped {
+			continue
+		}
+		for _, name := range names {
+			if i := indexOf(name); i != -1 {
+				result[field] = i
+				break
+			}
+		}
+	}
+	return result, nil
+}
+
+func addRow(b *builder.RecordBuilder, parseDate func(string) (klog.Date, error), value func(string) string) error {
+	date, err := parseDate(value(FIELD_DATE))
+	if err != nil {
+		return errors.New("Invalid date")
+	}
+	summary, sErr := toSummary(value(FIELD_DESCRIPTION), value(FIELD_TAGS), value(FIELD_PROJECT))
+	if sErr != nil {
+		return sErr
+	}
+
+	if value(FIELD_START) != "" {
+		start, stErr := parseTime(date, value(FIELD_START))
+		if stErr != nil {
+			return errors.New("Invalid start time")
+		}
+		if value(FIELD_END) == "" {
+			return b.AddOpenRangeAt(date, start, summary)
+		}
+		endDate := date
+		if value(FIELD_END_DATE) != "" {
+			d, dErr := parseDate(value(FIELD_END_DATE))
+			if dErr != nil {
+				return errors.New("Invalid end date")
+			}
+			endDate = d
+		}
+		end, eErr := parseTime(endDate, value(FIELD_END))
+		if eErr != nil {
+			return errors.New("Invalid end time")
+		}
+		if value(FIELD_END_DATE) == "" && end.Before(start) {
+			end = end.AddDate(0, 0, 1)
+		}
+		return b.AddRangeAt(date, start, end, summary)
+	}
+
+	if value(FIELD_DURATION) != "" {
+		d, dErr := parseDuration(value(FIELD_DURATION))
+		if dErr != nil {
+			return errors.New("Invalid duration")
+		}
+		b.AddDuration(date, d, summary)
+		return nil
+	}
+
+	if value(FIELD_DURATION_MINS) != "" {
+		mins, dErr := strconv.Atoi(value(FIELD_DURATION_MINS))
+		if dErr != nil {
+			return errors.New("Invalid duration")
+		}
+		b.AddDuration(date, klog.NewDuration(0, mins), summary)
+		return nil
+	}
+
+	return errors.New("There is neither a start time nor a duration")
+}
+
+func toSummary(description string, tags string, project string) (klog.EntrySummary, error) {
+	var texts []string
+	if project != "" {
+		texts = append(texts, project)
+	}
+	if strings.Contains(tags, "#") {
+		// The tags are in klog notation, e.g. `#foo #bar=1`.
+		texts = append(texts, klog.HashTagPattern.FindAllString(tags, -1)...)
+	} else {
+		texts = append(texts, strings.Split(tags, ",")...)
+	}
+	var result []klog.Tag
+	for _, text := range texts {
+		if strings.TrimSpace(text) == "" {
+			continue
+		}
+		tag, err := builder.NewTag(text)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, tag)
+	}
+	summary, err := builder.NewSummary(description, result)
+	if err != nil {
+		return nil, errors.New("Invalid description")
+	}
+	return summary, nil
+}
+
+var dateFormatPattern = regexp.MustCompile(`^(YYYY|MM|DD|[^\p{L}\d])+$`)
+
+// newDateParser returns a function that parses dates in the given format.
+// Without format, the dates are parsed in klog’s notation.
+func newDateParser(format string) (func(string) (klog.Date, error), error) {
+	if format == "" {
+		return klog.NewDateFromString, nil
+	}
+	if !dateFormatPattern.MatchString(format) || strings.Count(format, "YYYY") != 1 ||
+		strings.Count(format, "MM") != 1 || strings.Count(format, "DD") != 1 {
+		return nil, errors.New("Invalid date format `" + format + "`")
+	}
+	// The month and day may have one or two digits.
+	layout := strings.NewReplacer("YYYY", "2006", "MM", "1", "DD", "2").Replace(format)
+	return func(value string) (klog.Date, error) {
+		t, err := gotime.Parse(layout, value)
+		if err != nil {
+			return nil, err
+		}
+		return klog.NewDateFromGo(t), nil
+	}, nil
+}
+
+var timePattern = regexp.MustCompile(`^(\d{1,2}):(\d{2})(:\d{2})?\s*([aApP][mM])?$`)
+
+// parseTime parses a time value on the given date. Besides klog’s own notation
+// (including shifted times like `<23:00`), it accepts times with seconds or with
+// an upper-case am/pm suffix, e.g. `09:00:00` or `9:00 AM`.
+func parseTime(date klog.Date, value string) (gotime.Time, error) {
+	midnight := gotime.Date(date.Year(), gotime.Month(date.Month()), date.Day(), 0, 0, 0, 0, gotime.UTC)
+	t, err := klog.NewTimeFromString(value)
+	if err != nil {
+		match := timePattern.FindStringSubmatch(value)
+		if match == nil {
+			return midnight, errors.New("Invalid time")
+		}
+		t, err = klog.NewTimeFromString(match[1] + ":" + match[2] + strings.ToLower(match[4]))
+		if err != nil {
+			return midnight, errors.New("Invalid time")
+		}
+	}
+	return midnight.Add(gotime.Duration(t.MidnightOffset().InMinutes()) * gotime.Minute), nil
+}
+
+var durationPattern = regexp.MustCompile(`^(-)?(\d+):(\d{2})(:\d{2})?$`)
+
+// parseDuration parses a duration value. Besides klog’s own notation, it
+// accepts the `h:mm` or `h:mm:ss` notation, or decimal hours (e.g. `1.5`).
+func parseDuration(value string) (klog.Duration, error) {
+	d, err := klog.NewDurationFromString(value)
+	if err == nil {
+		return d, nil
+	}
+	if match := durationPattern.FindStringSubmatch(value); match != nil {
+		sign := 1
+		if match[1] == "-" {
+			sign = -1
+		}
+		hours, _ := strconv.Atoi(match[2])
+		minutes, _ := strconv.Atoi(match[3])
+		if minutes >= 60 {
+			return nil, errors.New("Invalid duration")
+		}
+		return klog.NewDuration(sign*hours, sign*minutes), nil
+	}
+	hours, fErr := strconv.ParseFloat(strings.Replace(value, ",", ".", 1), 64)
+	if fErr != nil || math.IsNaN(hours) || math.IsInf(hours, 0) {
+		return nil, errors.New("Invalid duration")
+	}
+	return klog.NewDuration(0, int(math.Round(hours*60))), nil
+}
@@ -0,0 +1,171 @@
+package csv
+
+import (
+	"testing"
+
+	"github.com/jotaen/klog/klog"
+	"github.com/jotaen/klog/klog/parser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func entryTexts(rs []klog.Record) []string {
+	var result []string
+	for _, r := range rs {
+		for _, e := range r.Entries() {
+			value := klog.Unbox(&e,
+				func(tr klog.Range) string { return tr.ToString() },
+				func(d klog.Duration) string { return d.ToString() },
+				func(o klog.OpenRange) string { return o.ToString() },
+			)
+			summary := parser.SummaryText(e.Summary()).ToString()
+			if summary != "" {
+				value += " " + summary
+			}
+			result = append(result, r.Date().ToString()+" "+value)
+		}
+	}
+	return result
+}
+
+var defaultReadOptions = ReadOptions{Delimiter: ','}
+
+func TestDeserialiseEmptyInput(t *testing.T) {
+	rs, err := FromCsv("", defaultReadOptions)
+	require.Nil(t, err)
+	assert.Len(t, rs, 0)
+
+	rs, err = FromCsv("date,duration\n", defaultReadOptions)
+	require.Nil(t, err)
+	assert.Len(t, rs, 0)
+}
+
+func TestDeserialiseTogglStyleExport(t *testing.T) {
+	rs, err := FromCsv(`User,Email,Client,Project,Task,Description,Billable,Start date,Start time,End date,End time,Duration,Tags
+Jane,jane@example.com,ACME,Website Relaunch,,Design review,Yes,2024-01-02,09:00:00,2024-01-02,10:30:00,01:30:00,"review, Client Work"
+Jane,jane@example.com,ACME,,,Deployment,Yes,2024-01-01,23:00:00,2024-01-02,01:15:00,02:15:00,
+
+Jane,jane@example.com,,,,,No,2024-01-02,13:00:00,2024-01-02,13:45:00,00:45:00,
+`, defaultReadOptions)
+	require.Nil(t, err)
+	assert.Equal(t, []string{
+		"2024-01-01 23:00 - 1:15> Deployment",
+		"2024-01-02 9:00 - 10:30 Design review #website_relaunch #review #client_work",
+		"2024-01-02 13:00 - 13:45",
+	}, entryTexts(rs))
+}
+
+func TestDeserialiseClockifyStyleExport(t *testing.T) {
+	rs, err := FromCsv(`Project;Description;Tags;Start Date;Start Time;End Date;End Time;Duration (h);Duration (decimal)
+Internal;Weekly sync;meeting;2024/01/03;02:00 PM;2024/01/03;03:00 PM;01:00:00;1,00
+`, ReadOptions{Delimiter: ';'})
+	require.Nil(t, err)
+	assert.Equal(t, []string{
+		"2024-01-03 14:00 - 15:00 Weekly sync #internal #meeting",
+	}, entryTexts(rs))
+}
+
+func TestDeserialiseWithDateFormat(t *testing.T) {
+	for _, x := range []struct {
+		format string
+		dates  [2]string
+	}{
+		{"MM/DD/YYYY", [2]string{"01/31/2024", "2/1/2024"}},
+		{"DD.MM.YYYY", [2]string{"31.01.2024", "1.2.2024"}},
+		{"YYYY-MM-DD", [2]string{"2024-01-31", "2024-02-01"}},
+	} {
+		rs, err := FromCsv("Start date,Start time,End date,End time\n"+
+			x.dates[0]+",23:00,"+x.dates[1]+",1:00\n",
+			ReadOptions{Delimiter: ',', DateFormat: x.format})
+		require.Nil(t, err, x.format)
+		assert.Equal(t, []string{"2024-01-31 23:00 - 1:00>"}, entryTexts(rs), x.format)
+	}
+}
+
+func TestDeserialiseShiftedTimesIntoRowDate(t *testing.T) {
+	rs, err := FromCsv(`date,start,end
+2024-01-02,<23:00,1:00
+2024-01-03,<23:30,
+2024-01-03,22:00,0:30>
+`, defaultReadOptions)
+	require.Nil(t, err)
+	assert.Equal(t, []string{
+		"2024-01-02 <23:00 - 1:00",
+		"2024-01-03 <23:30 - ?",
+		"2024-01-03 22:00 - 0:30>",
+	}, entryTexts(rs))
+}
+
+func TestDeserialiseDurationsAndOpenRanges(t *testing.T) {
+	rs, err := FromCsv(`Date,Duration,Start,Notes
+2024-01-01,1.5,,Decimal
+2024-01-01,1:15,,Colon notation
+2024-01-01,-0:30,,Negative
+2024-01-01,2h5m,,klog notation
+2024-01-02,,8:00,Open
+`, ReadOptions{Delimiter: ',', Columns: map[string]string{FIELD_DESCRIPTION: "notes"}})
+	require.Nil(t, err)
+	assert.Equal(t, []string{
+		"2024-01-01 1h30m Decimal",
+		"2024-01-01 1h15m Colon notation",
+		"2024-01-01 -30m Negative",
+		"2024-01-01 2h5m klog notation",
+		"2024-01-02 8:00 - ? Open",
+	}, entryTexts(rs))
+}
+
+func TestRoundTrip(t *testing.T) {
+	original := func() []klog.Record {
+		r := klog.NewRecord(klog.Ɀ_Date_(2000, 12, 31))
+		r.SetSummary(klog.Ɀ_RecordSummary_("#work"))
+		r.AddDuration(klog.NewDuration(2, 3), klog.Ɀ_EntrySummary_("Some #thing, with comma"))
+		r.AddRange(klog.Ɀ_Range_(klog.Ɀ_Time_(22, 0), klog.Ɀ_TimeTomorrow_(0, 30)), klog.Ɀ_EntrySummary_("#late=yes"))
+		return []klog.Record{r}
+	}()
+	rs, err := FromCsv(ToCsv(original, Options{Delimiter: ','}), defaultReadOptions)
+	require.Nil(t, err)
+	assert.Equal(t, []string{
+		"2000-12-31 2h3m Some #thing, with comma #work",
+		"2000-12-31 22:00 - 0:30> #late=yes #work",
+	}, entryTexts(rs))
+}
+
+func TestDeserialiseFailsForInvalidInput(t *testing.T) {
+	for _, x := range []struct {
+		text    string
+		columns map[string]string
+		msg     string
+	}{
+		{"start,end\n", nil, "There is no column for the date"},
+		{"date,duration\n", map[string]string{"foo": "date"}, "Unknown field `foo`"},
+		{"date,duration\n", map[string]string{FIELD_DESCRIPTION: "notes"}, "There is no column `notes`"},
+		{"date,duration\n2024-13-01,1h", nil, "Line 2: Invalid date"},
+		{"date,duration\n2024-01-01,1h\n2024-01-01,x", nil, "Line 3: Invalid duration"},
+		{"date,duration,start\n2024-01-01,,25:00", nil, "Line 2: Invalid start time"},
+		{"date,start,end\n2024-01-01,8:00,x", nil, "Line 2: Invalid end time"},
+		{"date,start,end,end date\n2024-01-02,8:00,7:00,2024-01-01", nil, "Line 2: Start and end time must be in chronological order"},
+		{"date,start\n2024-01-01,8:00\n2024-01-01,9:00", nil, "Line 3: There can only be one open range per record"},
+		{"date,duration,tags\n2024-01-01,1h,\"foo,%&\"", nil, "Line 2: Invalid tag: `%&`"},
+		{"date,description\n2024-01-01,foo", nil, "Line 2: There is neither a start time nor a duration"},
+		{"date,description\n2024-01-01,\"foo", nil, `Malformed CSV: parse error on line 2, column 16: extraneous or missing " in quoted-field`},
+	} {
+		rs, err := FromCsv(x.text, ReadOptions{Delimiter: ',', Columns: x.columns})
+		require.Error(t, err, x.text)
+		assert.Equal(t, x.msg, err.Error())
+		assert.Nil(t, rs)
+	}
+}
+
+func TestDeserialiseFailsForInvalidDateFormat(t *testing.T) {
+	for _, format := range []string{"MM/DD", "YYYY-MM-DD-DD", "YYYY-MMM-DD", "Jan 2, 2006"} {
+		rs, err := FromCsv("date,duration\n2024-01-01,1h", ReadOptions{Delimiter: ',', DateFormat: format})
+		require.Error(t, err, format)
+		assert.Equal(t, "Invalid date format `"+format+"`", err.Error())
+		assert.Nil(t, rs)
+	}
+
+	rs, err := FromCsv("date,duration\n2024-01-01,1h", ReadOptions{Delimiter: ',', DateFormat: "DD.MM.YYYY"})
+	require.Error(t, err)
+	assert.Equal(t, "Line 2: Invalid date", err.Error())
+	assert.Nil(t, rs)
+}
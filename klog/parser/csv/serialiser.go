@@ -0,0 +1,94 @@
+/*
+Package csv contains the logic of serialising Record’s as CSV (comma-separated
+values), with one row per entry.
+*/
+package csv
+
+import (
+	"bytes"
+	"encoding/csv"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/jotaen/klog/klog"
+	"github.com/jotaen/klog/klog/parser"
+)
+
+// Options controls the shape of the CSV output.
+type Options struct {
+	// Delimiter is the character that separates the fields of a row.
+	Delimiter rune
+
+	// OmitHeader suppresses the header row with the column names.
+	OmitHeader bool
+}
+
+// Columns are the names of the columns, as printed in the header row.
+var Columns = []string{
+	"date",
+	"type",
+	"start",
+	"end",
+	"duration_mins",
+	"record_summary",
+	"entry_summary",
+	"tags",
+}
+
+// ToCsv serialises records into CSV. Every entry yields one row; records
+// without entries don’t appear in the output.
+func ToCsv(rs []klog.Record, opts Options) string {
+	buffer := new(bytes.Buffer)
+	w := csv.NewWriter(buffer)
+	w.Comma = opts.Delimiter
+	if !opts.OmitHeader {
+		_ = w.Write(Columns)
+	}
+	for _, r := range rs {
+		for _, e := range r.Entries() {
+			_ = w.Write(toRow(r, e))
+		}
+	}
+	w.Flush()
+	if w.Error() != nil {
+		panic(w.Error()) // This should never happen
+	}
+	return buffer.String()
+}
+
+func toRow(r klog.Record, e klog.Entry) []string {
+	typeStartEnd := klog.Unbox(&e, func(tr klog.Range) []string {
+		return []string{"range", tr.Start().ToString(), tr.End().ToString()}
+	}, func(d klog.Duration) []string {
+		return []string{"duration", "", ""}
+	}, func(o klog.OpenRange) []string {
+		return []string{"open", o.Start().ToString(), ""}
+	})
+	return []string{
+		r.Date().ToString(),
+		typeStartEnd[0],
+		typeStartEnd[1],
+		typeStartEnd[2],
+		strconv.Itoa(e.Duration().InMinutes()),
+		parser.SummaryText(r.Summary()).ToString(),
+		parser.SummaryText(e.Summary()).ToString(),
+		toTags(r, e),
+	}
+}
+
+// toTags returns all tags that apply to the entry, i.e. the ones from the
+// record summary and the ones from the entry summary.
+func toTags(r klog.Record, e klog.Entry) string {
+	var result []string
+	seen := make(map[string]bool)
+	for _, t := range append(r.Summary().Tags().ToStrings(), e.Summary().Tags().ToStrings()...) {
+		if seen[t] {
+			continue
+		}
+		seen[t] = true
+		result = append(result, t)
+	}
+	sort.Strings(result)
+	return strings.Join(result, " ")
+}
@@ -0,0 +1,75 @@
+package csv
+
+import (
+	"testing"
+
+	"github.com/jotaen/klog/klog"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSerialiseNoRecords(t *testing.T) {
+	csv := ToCsv(nil, Options{Delimiter: ','})
+	assert.Equal(t, "date,type,start,end,duration_mins,record_summary,entry_summary,tags\n", csv)
+}
+
+func TestSerialiseNoRecordsWithoutHeader(t *testing.T) {
+	csv := ToCsv(nil, Options{Delimiter: ',', OmitHeader: true})
+	assert.Equal(t, "", csv)
+}
+
+func TestSerialiseRecordWithoutEntries(t *testing.T) {
+	csv := ToCsv([]klog.Record{klog.NewRecord(klog.Ɀ_Date_(2000, 12, 31))}, Options{Delimiter: ',', OmitHeader: true})
+	assert.Equal(t, "", csv)
+}
+
+func TestSerialiseFullBlownRecords(t *testing.T) {
+	csv := ToCsv(func() []klog.Record {
+		r1 := klog.NewRecord(klog.Ɀ_Date_(2000, 12, 31))
+		r1.SetSummary(klog.Ɀ_RecordSummary_("Hello #World", "What’s up?"))
+		r1.SetShouldTotal(klog.NewDuration(7, 30))
+		r1.AddDuration(klog.NewDuration(2, 3), klog.Ɀ_EntrySummary_("#some #thing, with comma"))
+		r1.AddRange(klog.Ɀ_Range_(klog.Ɀ_TimeYesterday_(23, 44), klog.Ɀ_Time_(5, 23)), nil)
+		r1.Start(klog.NewOpenRange(klog.Ɀ_TimeTomorrow_(0, 28)), klog.Ɀ_EntrySummary_("Started #todo=nr4", "still on #it #World"))
+		r2 := klog.NewRecord(klog.Ɀ_Date_(2001, 1, 1))
+		r2.AddDuration(klog.NewDuration(-1, -15), nil)
+		r2.AddRange(klog.Ɀ_Range_(klog.Ɀ_IsAmPm_(klog.Ɀ_Time_(22, 0)), klog.Ɀ_TimeTomorrow_(1, 0)), klog.Ɀ_EntrySummary_(`Say "hi"`))
+		return []klog.Record{r1, r2}
+	}(), Options{Delimiter: ','})
+	assert.Equal(t, `date,type,start,end,duration_mins,record_summary,entry_summary,tags
+2000-12-31,duration,,,123,"Hello #World
+What’s up?","#some #thing, with comma",#some #thing #world
+2000-12-31,range,<23:44,5:23,339,"Hello #World
+What’s up?",,#world
+2000-12-31,open,0:28>,,0,"Hello #World
+What’s up?","Started #todo=nr4
+still on #it #World",#it #todo=nr4 #world
+2001-01-01,duration,,,-75,,,
+2001-01-01,range,10:00pm,1:00>,180,,"Say ""hi""",
+`, csv)
+}
+
+func TestSerialiseWithCustomDelimiter(t *testing.T) {
+	csv := ToCsv(func() []klog.Record {
+		r := klog.NewRecord(klog.Ɀ_Date_(2000, 12, 31))
+		r.AddDuration(klog.NewDuration(1, 0), klog.Ɀ_EntrySummary_("Foo; bar #baz"))
+		return []klog.Record{r}
+	}(), Options{Delimiter: ';', OmitHeader: false})
+	assert.Equal(t, `date;type;start;end;duration_mins;record_summary;entry_summary;tags
+2000-12-31;duration;;;60;;"Foo; bar #baz";#baz
+`, csv)
+}
+
+func TestSerialiseMultilineSummaries(t *testing.T) {
+	csv := ToCsv(func() []klog.Record {
+		r := klog.NewRecord(klog.Ɀ_Date_(2000, 12, 31))
+		r.SetSummary(klog.Ɀ_RecordSummary_("First line", "Second line", "Third line"))
+		r.AddDuration(klog.NewDuration(1, 0), klog.Ɀ_EntrySummary_("Entry line", "Continued", "And continued"))
+		return []klog.Record{r}
+	}(), Options{Delimiter: ',', OmitHeader: true})
+	assert.Equal(t, `2000-12-31,duration,,,60,"First line
+Second line
+Third line","Entry line
+Continued
+And continued",
+`, csv)
+}
@@ -0,0 +1,274 @@
+package ical
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	gotime "time"
+
+	"github.com/jotaen/klog/klog"
+	"github.com/jotaen/klog/klog/parser/builder"
+)
+
+type event struct {
+	uid        string
+	summary    string
+	categories []string
+	start      *gotime.Time
+	end        *gotime.Time
+	duration   *gotime.Duration
+	isAllDay   bool
+	isCanceled bool
+}
+
+// FromIcal reads the events from iCalendar data and converts them to records.
+// Every event yields a range entry in the record at the event’s start date;
+// the event title becomes the entry summary and the categories become tags.
+// All-day events and cancelled events are skipped. Other events that cannot
+// be represented as range (e.g. events that span more than two days) are
+// skipped as well, and for each of them, there is a message about the reason.
+// Times that are bound to a time zone are converted to `location`; floating
+// times are taken as they are. The resulting records are sorted by date.
+func FromIcal(text string, location *gotime.Location) ([]klog.Record, []string, error) {
+	events, err := parseEvents(text, location)
+	if err != nil {
+		return nil, nil, err
+	}
+	b := builder.NewRecordBuilder()
+	var skipped []string
+	for _, e := range events {
+		if e.isCanceled || e.isAllDay {
+			continue
+		}
+		if e.start == nil {
+			skipped = append(skipped, e.describe()+": There is no start time")
+			continue
+		}
+		end := func() *gotime.Time {
+			if e.end != nil {
+				return e.end
+			}
+			if e.duration != nil {
+				end := e.start.Add(*e.duration)
+				return &end
+			}
+			return nil
+		}()
+		if end == nil {
+			skipped = append(skipped, e.describe()+": There is neither an end time nor a duration")
+			continue
+		}
+		var tags []klog.Tag
+		for _, c := range e.categories {
+			if strings.TrimSpace(c) == "" {
+				continue
+			}
+			tag, tErr := builder.NewTag(c)
+			if tErr != nil {
+				return nil, nil, fmt.Errorf("Event `%s`: %w", e.summary, tErr)
+			}
+			tags = append(tags, tag)
+		}
+		summary, sErr := builder.NewSummary(e.summary, tags)
+		if sErr != nil {
+			return nil, nil, fmt.Errorf("Event `%s`: %w", e.summary, sErr)
+		}
+		rErr := b.AddRange(*e.start, *end, summary)
+		if rErr != nil {
+			skipped = append(skipped, e.describe()+": "+rErr.Error())
+		}
+	}
+	return b.Records(), skipped, nil
+}
+
+// describe identifies the event for the user, e.g.: Event `Standup` (UID 123).
+func (e event) describe() string {
+	result := "Event `" + e.summary + "`"
+	if e.uid != "" {
+		result += " (UID " + e.uid + ")"
+	}
+	return result
+}
+
+func parseEvents(text string, location *gotime.Location) ([]event, error) {
+	var events []event
+	var components []string
+	var current *event
+	for i, line := range unfold(text) {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		name, params, value, pErr := parseContentLine(line)
+		if pErr != nil {
+			return nil, fmt.Errorf("Line %d: %w", i+1, pErr)
+		}
+		switch name {
+		case "BEGIN":
+			components = append(components, strings.ToUpper(value))
+			if strings.ToUpper(value) == "VEVENT" {
+				current = &event{}
+			}
+			continue
+		case "END":
+			if len(components) == 0 || components[len(components)-1] != strings.ToUpper(value) {
+				return nil, fmt.Errorf("Line %d: Unexpected end of component `%s`", i+1, value)
+			}
+			components = components[:len(components)-1]
+			if strings.ToUpper(value) == "VEVENT" {
+				events = append(events, *current)
+				current = nil
+			}
+			continue
+		}
+		if current == nil || components[len(components)-1] != "VEVENT" {
+			// Only consider the properties that belong directly to an event.
+			continue
+		}
+		switch name {
+		case "UID":
+			current.uid = value
+		case "SUMMARY":
+			current.summary = unescape(value)
+		case "CATEGORIES":
+			current.categories = append(current.categories, splitList(value)...)
+		case "STATUS":
+			current.isCanceled = strings.ToUpper(value) == "CANCELLED"
+		case "DTSTART", "DTEND":
+			if strings.ToUpper(params["VALUE"]) == "DATE" || len(value) == 8 {
+				current.isAllDay = true
+				continue
+			}
+			t, tErr := parseDateTime(value, params["TZID"], location)
+			if tErr != nil {
+				return nil, fmt.Errorf("Line %d: %w", i+1, tErr)
+			}
+			if name == "DTSTART" {
+				current.start = &t
+			} else {
+				current.end = &t
+			}
+		case "DURATION":
+			d, dErr := parseDuration(value)
+			if dErr != nil {
+				return nil, fmt.Errorf("Line %d: %w", i+1, dErr)
+			}
+			current.duration = &d
+		}
+	}
+	if len(components) > 0 {
+		return nil, errors.New("Unexpected end of data")
+	}
+	return events, nil
+}
+
+// unfold splits the text into content lines, whereby folded lines are
+// joined, as per RFC 5545, section 3.1.
+func unfold(text string) []string {
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	text = strings.ReplaceAll(text, "\n ", "")
+	text = strings.ReplaceAll(text, "\n\t", "")
+	return strings.Split(text, "\n")
+}
+
+// parseContentLine splits up a content line into its name, its parameters
+// and its value, e.g.: `DTSTART;TZID=Europe/Berlin:20240101T090000`.
+func parseContentLine(line string) (string, map[string]string, string, error) {
+	isQuoted := false
+	for i, c := range line {
+		if c == '"' {
+			isQuoted = !isQuoted
+		}
+		if c != ':' || isQuoted {
+			continue
+		}
+		nameAndParams := strings.Split(line[:i], ";")
+		params := make(map[string]string)
+		for _, p := range nameAndParams[1:] {
+			k, v, _ := strings.Cut(p, "=")
+			params[strings.ToUpper(k)] = strings.Trim(v, `"`)
+		}
+		return strings.ToUpper(nameAndParams[0]), params, line[i+1:], nil
+	}
+	return "", nil, "", errors.New("Malformed content line")
+}
+
+func parseDateTime(value string, tzid string, location *gotime.Location) (gotime.Time, error) {
+	if strings.HasSuffix(value, "Z") {
+		t, err := gotime.Parse("20060102T150405Z", value)
+		if err != nil {
+			return t, errors.New("Invalid date-time value")
+		}
+		return t.In(location), nil
+	}
+	sourceLocation := location
+	if tzid != "" {
+		l, lErr := gotime.LoadLocation(tzid)
+		if lErr == nil {
+			sourceLocation = l
+		}
+	}
+	t, err := gotime.ParseInLocation("20060102T150405", value, sourceLocation)
+	if err != nil {
+		return t, errors.New("Invalid date-time value")
+	}
+	return t.In(location), nil
+}
+
+var durationPattern = regexp.MustCompile(`^\+?P(?:(\d+)W)?(?:(\d+)D)?(?:T(?:(\d+)H)?(?:(\d+)M)?(?:(\d+)S)?)?$`)
+
+// parseDuration parses durations as per RFC 5545, section 3.3.6,
+// e.g. `PT1H30M`.
+func parseDuration(value string) (gotime.Duration, error) {
+	match := durationPattern.FindStringSubmatch(value)
+	if match == nil {
+		return 0, errors.New("Invalid duration value")
+	}
+	units := []gotime.Duration{7 * 24 * gotime.Hour, 24 * gotime.Hour, gotime.Hour, gotime.Minute, gotime.Second}
+	var result gotime.Duration
+	for i, u := range units {
+		if match[i+1] == "" {
+			continue
+		}
+		n, _ := strconv.Atoi(match[i+1])
+		result += gotime.Duration(n) * u
+	}
+	return result, nil
+}
+
+// splitList splits up a list of text values at the (unescaped) commas.
+func splitList(value string) []string {
+	var result []string
+	current := ""
+	isEscaped := false
+	for _, c := range value {
+		if isEscaped {
+			current += `\` + string(c)
+			isEscaped = false
+			continue
+		}
+		if c == '\\' {
+			isEscaped = true
+			continue
+		}
+		if c == ',' {
+			result = append(result, unescape(current))
+			current = ""
+			continue
+		}
+		current += string(c)
+	}
+	return append(result, unescape(current))
+}
+
+// unescape is the counterpart of `escape`.
+func unescape(text string) string {
+	return strings.NewReplacer(
+		`\\`, `\`,
+		`\;`, ";",
+		`\,`, ",",
+		`\n`, "\n",
+		`\N`, "\n",
+	).Replace(text)
+}
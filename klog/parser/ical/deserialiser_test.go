@@ -0,0 +1,147 @@
+package ical
+
+import (
+	"strings"
+	"testing"
+	gotime "time"
+
+	"github.com/jotaen/klog/klog"
+	"github.com/jotaen/klog/klog/parser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func serialise(rs []klog.Record) string {
+	result := ""
+	for i, r := range rs {
+		if i > 0 {
+			result += "\n"
+		}
+		result += r.Date().ToString() + "\n"
+		for _, e := range r.Entries() {
+			value := klog.Unbox(&e,
+				func(tr klog.Range) string { return tr.ToString() },
+				func(d klog.Duration) string { return d.ToString() },
+				func(o klog.OpenRange) string { return o.ToString() },
+			)
+			result += "    " + strings.TrimSpace(value+" "+parser.SummaryText(e.Summary()).ToString()) + "\n"
+		}
+	}
+	return result
+}
+
+func TestDeserialiseEmptyCalendar(t *testing.T) {
+	rs, skipped, err := FromIcal("BEGIN:VCALENDAR\r\nVERSION:2.0\r\nEND:VCALENDAR\r\n", gotime.UTC)
+	require.Nil(t, err)
+	assert.Len(t, rs, 0)
+	assert.Nil(t, skipped)
+}
+
+func TestDeserialiseEvents(t *testing.T) {
+	rs, skipped, err := FromIcal(`BEGIN:VCALENDAR
+VERSION:2.0
+BEGIN:VEVENT
+UID:1
+DTSTART:20240102T140000
+DTEND:20240102T153000
+SUMMARY:Planning\, part 2
+CATEGORIES:Project X,work=abc
+CATEGORIES:meeting
+BEGIN:VALARM
+SUMMARY:Ignored
+END:VALARM
+END:VEVENT
+BEGIN:VEVENT
+UID:2
+DTSTART:20240101T230000
+DURATION:PT1H15M
+SUMMARY:Night
+ shift
+END:VEVENT
+BEGIN:VEVENT
+UID:3
+DTSTART:20240102T090000Z
+DTEND:20240102T093000Z
+END:VEVENT
+BEGIN:VEVENT
+UID:4
+DTSTART;TZID=America/New_York:20240102T080000
+DTEND;TZID=America/New_York:20240102T083000
+SUMMARY:Remote call
+END:VEVENT
+BEGIN:VEVENT
+UID:5
+DTSTART;VALUE=DATE:20240103
+DTEND;VALUE=DATE:20240104
+SUMMARY:All-day event (skipped)
+END:VEVENT
+BEGIN:VEVENT
+UID:6
+DTSTART:20240103T100000
+DTEND:20240103T110000
+STATUS:CANCELLED
+SUMMARY:Cancelled event (skipped)
+END:VEVENT
+BEGIN:VEVENT
+UID:7
+DTSTART:20240103T100000
+DTEND:20240105T110000
+SUMMARY:Multi-day event (skipped)
+END:VEVENT
+BEGIN:VEVENT
+DTSTART:20240103T100000
+SUMMARY:Event without end (skipped)
+END:VEVENT
+END:VCALENDAR
+`, gotime.FixedZone("CET", 60*60))
+	require.Nil(t, err)
+	assert.Equal(t, []string{
+		"Event `Multi-day event (skipped)` (UID 7): The time span must not exceed two days",
+		"Event `Event without end (skipped)`: There is neither an end time nor a duration",
+	}, skipped)
+	assert.Equal(t, `2024-01-01
+    23:00 - 0:15> Nightshift
+
+2024-01-02
+    14:00 - 15:30 Planning, part 2 #project_x #work=abc #meeting
+    10:00 - 10:30
+    14:00 - 14:30 Remote call
+`, serialise(rs))
+}
+
+func TestRoundTripRanges(t *testing.T) {
+	original := func() []klog.Record {
+		r1 := klog.NewRecord(klog.Ɀ_Date_(2000, 12, 31))
+		r1.AddRange(klog.Ɀ_Range_(klog.Ɀ_TimeYesterday_(23, 44), klog.Ɀ_Time_(5, 23)), klog.Ɀ_EntrySummary_("Night shift #work"))
+		r1.AddRange(klog.Ɀ_Range_(klog.Ɀ_Time_(22, 0), klog.Ɀ_TimeTomorrow_(0, 30)), nil)
+		return []klog.Record{r1}
+	}()
+	rs, skipped, err := FromIcal(ToIcal(original, now), gotime.UTC)
+	require.Nil(t, err)
+	assert.Nil(t, skipped)
+	assert.Equal(t, `2000-12-30
+    23:44 - 5:23> Night shift #work
+
+2000-12-31
+    22:00 - 0:30>
+`, serialise(rs))
+}
+
+func TestDeserialiseFailsForInvalidInput(t *testing.T) {
+	for _, x := range []struct {
+		text string
+		msg  string
+	}{
+		{"BEGIN:VCALENDAR\nBEGIN:VEVENT\nEND:VCALENDAR", "Line 3: Unexpected end of component `VCALENDAR`"},
+		{"BEGIN:VCALENDAR\nBEGIN:VEVENT\nEND:VEVENT", "Unexpected end of data"},
+		{"BEGIN:VCALENDAR\nasdf\nEND:VCALENDAR", "Line 2: Malformed content line"},
+		{"BEGIN:VEVENT\nDTSTART:2024-01-01\nEND:VEVENT", "Line 2: Invalid date-time value"},
+		{"BEGIN:VEVENT\nDURATION:1h\nEND:VEVENT", "Line 2: Invalid duration value"},
+		{"BEGIN:VEVENT\nSUMMARY:Party\nCATEGORIES:Work,%&\nDTSTART:20240101T100000Z\nDTEND:20240101T110000Z\nEND:VEVENT", "Event `Party`: Invalid tag: `%&`"},
+	} {
+		rs, _, err := FromIcal(x.text, gotime.UTC)
+		require.Error(t, err, x.text)
+		assert.Equal(t, x.msg, err.Error())
+		assert.Nil(t, rs)
+	}
+}
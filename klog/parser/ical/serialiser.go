@@ -0,0 +1,169 @@
+/*
+Package ical contains the logic of serialising Record’s as iCalendar data
+(RFC 5545), e.g. for importing them into calendar applications.
+*/
+package ical
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"strings"
+	gotime "time"
+
+	"github.com/jotaen/klog/klog"
+	"github.com/jotaen/klog/klog/parser"
+)
+
+const lineEnding = "\r\n"
+
+// maxLineLength is the maximum number of octets of a content line, after
+// which the line must be folded.
+const maxLineLength = 75
+
+// ToIcal serialises records into an iCalendar object. Every range entry yields
+// an event with the respective start and end time. Every duration entry yields
+// an all-day event at the record’s date. Open ranges are omitted.
+// The times are “floating”, i.e. they are not bound to a particular time zone.
+// `now` is used as creation timestamp of the events.
+func ToIcal(rs []klog.Record, now gotime.Time) string {
+	var lines []string
+	lines = append(lines,
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:-//klog//klog//EN",
+		"CALSCALE:GREGORIAN",
+	)
+	timestamp := now.UTC().Format("20060102T150405Z")
+	for _, r := range rs {
+		for _, e := range r.Entries() {
+			event := toEvent(r, e)
+			if event == nil {
+				continue
+			}
+			lines = append(lines, "BEGIN:VEVENT", "UID:"+uid(r, e), "DTSTAMP:"+timestamp)
+			lines = append(lines, event...)
+			lines = append(lines, "END:VEVENT")
+		}
+	}
+	lines = append(lines, "END:VCALENDAR")
+
+	result := ""
+	for _, l := range lines {
+		result += fold(l) + lineEnding
+	}
+	return result
+}
+
+func toEvent(r klog.Record, e klog.Entry) []string {
+	summary := strings.Join(parser.SummaryText(e.Summary()), " ")
+	properties := klog.Unbox(&e, func(tr klog.Range) []string {
+		return []string{
+			"DTSTART:" + formatDateTime(r.Date(), tr.Start()),
+			"DTEND:" + formatDateTime(r.Date(), tr.End()),
+			"SUMMARY:" + escape(summary),
+		}
+	}, func(d klog.Duration) []string {
+		return []string{
+			"DTSTART;VALUE=DATE:" + formatDate(r.Date()),
+			"DTEND;VALUE=DATE:" + formatDate(r.Date().PlusDays(1)),
+			"SUMMARY:" + escape(strings.TrimSpace(d.ToString()+" "+summary)),
+			"TRANSP:TRANSPARENT",
+		}
+	}, func(o klog.OpenRange) []string {
+		return nil
+	})
+	if properties == nil {
+		return nil
+	}
+	description := parser.SummaryText(r.Summary()).ToString()
+	if description != "" {
+		properties = append(properties, "DESCRIPTION:"+escape(description))
+	}
+	categories := toCategories(r, e)
+	if len(categories) > 0 {
+		properties = append(properties, "CATEGORIES:"+strings.Join(categories, ","))
+	}
+	return properties
+}
+
+// toCategories returns all tags that apply to the entry (without the
+// leading `#`), i.e. the ones from the record summary and the ones from
+// the entry summary.
+func toCategories(r klog.Record, e klog.Entry) []string {
+	var result []string
+	seen := make(map[string]bool)
+	for _, t := range append(r.Summary().Tags().ToStrings(), e.Summary().Tags().ToStrings()...) {
+		tag, _ := klog.NewTagFromString(t)
+		c := tag.Name()
+		if tag.Value() != "" {
+			c += "=" + tag.Value()
+		}
+		c = escape(c)
+		if seen[c] {
+			continue
+		}
+		seen[c] = true
+		result = append(result, c)
+	}
+	return result
+}
+
+// uid derives a unique identifier for the event from the entry’s data, so
+// that it’s stable across multiple exports.
+func uid(r klog.Record, e klog.Entry) string {
+	value := klog.Unbox(&e,
+		func(tr klog.Range) string { return tr.ToString() },
+		func(d klog.Duration) string { return d.ToString() },
+		func(o klog.OpenRange) string { return o.ToString() },
+	)
+	hash := sha1.Sum([]byte(r.Date().ToString() + "\n" + value + "\n" + parser.SummaryText(e.Summary()).ToString()))
+	return formatDate(r.Date()) + "-" + hex.EncodeToString(hash[:8]) + "@klog"
+}
+
+func formatDate(d klog.Date) string {
+	return toGoTime(d, nil).Format("20060102")
+}
+
+func formatDateTime(d klog.Date, t klog.Time) string {
+	return toGoTime(d, t).Format("20060102T150405")
+}
+
+// toGoTime converts the date and the (optional) time to a Go time. Shifted
+// times (e.g. `<23:00`) are resolved to the respective previous or next day.
+func toGoTime(d klog.Date, t klog.Time) gotime.Time {
+	result := gotime.Date(d.Year(), gotime.Month(d.Month()), d.Day(), 0, 0, 0, 0, gotime.UTC)
+	if t != nil {
+		result = result.Add(gotime.Duration(t.MidnightOffset().InMinutes()) * gotime.Minute)
+	}
+	return result
+}
+
+// escape escapes text values as per RFC 5545, section 3.3.11.
+func escape(text string) string {
+	return strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\n", `\n`,
+	).Replace(text)
+}
+
+// fold splits up content lines that exceed the maximum length, as per
+// RFC 5545, section 3.1. It takes care not to split multi-byte characters.
+func fold(line string) string {
+	if len(line) <= maxLineLength {
+		return line
+	}
+	result := ""
+	lineLength := 0
+	for _, c := range line {
+		charLength := len(string(c))
+		if lineLength+charLength > maxLineLength {
+			result += lineEnding + " "
+			lineLength = 1
+		}
+		result += string(c)
+		lineLength += charLength
+	}
+	return result
+}
@@ -0,0 +1,92 @@
+package ical
+
+import (
+	"strings"
+	"testing"
+	gotime "time"
+
+	"github.com/jotaen/klog/klog"
+	"github.com/stretchr/testify/assert"
+)
+
+var now = gotime.Date(2001, 2, 3, 4, 5, 6, 0, gotime.UTC)
+
+func TestSerialiseEmptyCalendar(t *testing.T) {
+	ical := ToIcal(nil, now)
+	assert.Equal(t, "BEGIN:VCALENDAR\r\n"+
+		"VERSION:2.0\r\n"+
+		"PRODID:-//klog//klog//EN\r\n"+
+		"CALSCALE:GREGORIAN\r\n"+
+		"END:VCALENDAR\r\n", ical)
+}
+
+func TestSerialiseEvents(t *testing.T) {
+	ical := ToIcal(func() []klog.Record {
+		r := klog.NewRecord(klog.Ɀ_Date_(2000, 12, 31))
+		r.SetSummary(klog.Ɀ_RecordSummary_("Hello #World", "What’s up?"))
+		r.AddRange(klog.Ɀ_Range_(klog.Ɀ_TimeYesterday_(23, 44), klog.Ɀ_Time_(5, 23)), klog.Ɀ_EntrySummary_(`Night, shift; #work="a,b"`, "second line"))
+		r.AddDuration(klog.NewDuration(2, 3), nil)
+		r.AddRange(klog.Ɀ_Range_(klog.Ɀ_Time_(22, 0), klog.Ɀ_TimeTomorrow_(0, 30)), nil)
+		r.Start(klog.NewOpenRange(klog.Ɀ_Time_(1, 0)), klog.Ɀ_EntrySummary_("Omitted"))
+		return []klog.Record{r}
+	}(), now)
+	lines := strings.Split(ical, "\r\n")
+	assert.Equal(t, []string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:-//klog//klog//EN",
+		"CALSCALE:GREGORIAN",
+		"BEGIN:VEVENT",
+		lines[5],
+		"DTSTAMP:20010203T040506Z",
+		"DTSTART:20001230T234400",
+		"DTEND:20001231T052300",
+		`SUMMARY:Night\, shift\; #work="a\,b" second line`,
+		`DESCRIPTION:Hello #World\nWhat’s up?`,
+		`CATEGORIES:world,work=a\,b`,
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		lines[14],
+		"DTSTAMP:20010203T040506Z",
+		"DTSTART;VALUE=DATE:20001231",
+		"DTEND;VALUE=DATE:20010101",
+		"SUMMARY:2h3m",
+		"TRANSP:TRANSPARENT",
+		`DESCRIPTION:Hello #World\nWhat’s up?`,
+		"CATEGORIES:world",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		lines[24],
+		"DTSTAMP:20010203T040506Z",
+		"DTSTART:20001231T220000",
+		"DTEND:20010101T003000",
+		"SUMMARY:",
+		`DESCRIPTION:Hello #World\nWhat’s up?`,
+		"CATEGORIES:world",
+		"END:VEVENT",
+		"END:VCALENDAR",
+		"",
+	}, lines)
+	assert.Regexp(t, `^UID:20001231-[0-9a-f]{16}@klog$`, lines[5])
+	assert.NotEqual(t, lines[5], lines[14])
+	assert.NotEqual(t, lines[14], lines[24])
+}
+
+func TestSerialiseWithStableUids(t *testing.T) {
+	records := func() []klog.Record {
+		r := klog.NewRecord(klog.Ɀ_Date_(2000, 12, 31))
+		r.AddDuration(klog.NewDuration(1, 0), klog.Ɀ_EntrySummary_("Foo"))
+		return []klog.Record{r}
+	}
+	assert.Equal(t, ToIcal(records(), now), ToIcal(records(), now))
+}
+
+func TestFoldsLongLines(t *testing.T) {
+	assert.Equal(t, "SUMMARY:foo", fold("SUMMARY:foo"))
+	long := "SUMMARY:" + strings.Repeat("x", 70) + "äöü"
+	folded := fold(long)
+	assert.Equal(t, "SUMMARY:"+strings.Repeat("x", 67)+"\r\n xxxäöü", folded)
+	for _, l := range strings.Split(folded, "\r\n") {
+		assert.LessOrEqual(t, len(l), maxLineLength)
+	}
+}
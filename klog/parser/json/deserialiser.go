@@ -0,0 +1,134 @@
+package json
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/jotaen/klog/klog"
+)
+
+// envelopInput is the counterpart of Envelop for reading JSON. It only
+// contains the properties that are needed for re-constructing the records;
+// all evaluated data (such as totals) is disregarded.
+type envelopInput struct {
+	Records []recordInput `json:"records"`
+	Errors  []ErrorView   `json:"errors"`
+}
+
+type recordInput struct {
+	Date        string       `json:"date"`
+	Summary     string       `json:"summary"`
+	ShouldTotal string       `json:"should_total"`
+	Entries     []entryInput `json:"entries"`
+}
+
+type entryInput struct {
+	Type    string `json:"type"`
+	Summary string `json:"summary"`
+	Total   string `json:"total"`
+	Start   string `json:"start"`
+	End     string `json:"end"`
+}
+
+// FromJson deserialises records from their JSON representation, i.e. from the
+// output structure of ToJson. It returns an error if the JSON data is malformed
+// or if it doesn’t constitute valid records.
+func FromJson(text string) ([]klog.Record, error) {
+	var envelop envelopInput
+	dec := json.NewDecoder(bytes.NewBufferString(text))
+	err := dec.Decode(&envelop)
+	if err != nil {
+		return nil, errors.New("Malformed JSON: " + err.Error())
+	}
+	if len(envelop.Errors) > 0 {
+		return nil, errors.New("The JSON data contains errors instead of records")
+	}
+	var result []klog.Record
+	for i, rv := range envelop.Records {
+		r, rErr := fromRecordInput(rv)
+		if rErr != nil {
+			return nil, fmt.Errorf("Record %d (%s): %w", i+1, rv.Date, rErr)
+		}
+		result = append(result, r)
+	}
+	return result, nil
+}
+
+func fromRecordInput(rv recordInput) (klog.Record, error) {
+	date, err := klog.NewDateFromString(rv.Date)
+	if err != nil {
+		return nil, errors.New("Invalid date")
+	}
+	r := klog.NewRecord(date)
+
+	// The should-total is only set if it carries the `!` suffix,
+	// otherwise the `0m` value just denotes an absent should-total.
+	if strings.HasSuffix(rv.ShouldTotal, "!") {
+		should, sErr := klog.NewDurationFromString(strings.TrimSuffix(rv.ShouldTotal, "!"))
+		if sErr != nil {
+			return nil, errors.New("Invalid should-total")
+		}
+		r.SetShouldTotal(should)
+	}
+
+	if rv.Summary != "" {
+		summary, sErr := klog.NewRecordSummary(strings.Split(rv.Summary, "\n")...)
+		if sErr != nil {
+			return nil, errors.New("Invalid record summary")
+		}
+		r.SetSummary(summary)
+	}
+
+	for j, ev := range rv.Entries {
+		eErr := addEntryInput(r, ev)
+		if eErr != nil {
+			return nil, fmt.Errorf("Entry %d: %w", j+1, eErr)
+		}
+	}
+	return r, nil
+}
+
+func addEntryInput(r klog.Record, ev entryInput) error {
+	var summary klog.EntrySummary
+	if ev.Summary != "" {
+		s, sErr := klog.NewEntrySummary(strings.Split(ev.Summary, "\n")...)
+		if sErr != nil {
+			return errors.New("Invalid entry summary")
+		}
+		summary = s
+	}
+	switch ev.Type {
+	case "range":
+		start, sErr := klog.NewTimeFromString(ev.Start)
+		end, eErr := klog.NewTimeFromString(ev.End)
+		if sErr != nil || eErr != nil {
+			return errors.New("Invalid start or end time")
+		}
+		tr, rErr := klog.NewRange(start, end)
+		if rErr != nil {
+			return errors.New("Start and end time must be in chronological order")
+		}
+		r.AddRange(tr, summary)
+	case "duration":
+		d, dErr := klog.NewDurationFromString(ev.Total)
+		if dErr != nil {
+			return errors.New("Invalid duration")
+		}
+		r.AddDuration(d, summary)
+	case "open_range":
+		start, sErr := klog.NewTimeFromString(ev.Start)
+		if sErr != nil {
+			return errors.New("Invalid start time")
+		}
+		oErr := r.Start(klog.NewOpenRange(start), summary)
+		if oErr != nil {
+			return errors.New("There can only be one open range per record")
+		}
+	default:
+		return errors.New("Unknown entry type `" + ev.Type + "`")
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+package json
+
+import (
+	"testing"
+
+	"github.com/jotaen/klog/klog"
+	"github.com/jotaen/klog/klog/parser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeserialiseEmptyRecords(t *testing.T) {
+	rs, err := FromJson(`{"records":[],"warnings":null,"errors":null}`)
+	require.Nil(t, err)
+	assert.Len(t, rs, 0)
+}
+
+func TestDeserialiseMinimalRecord(t *testing.T) {
+	rs, err := FromJson(`{"records":[{"date":"2000-12-31","summary":"","should_total":"0m","entries":[]}]}`)
+	require.Nil(t, err)
+	require.Len(t, rs, 1)
+	assert.True(t, klog.Ɀ_Date_(2000, 12, 31).IsEqualTo(rs[0].Date()))
+	assert.Nil(t, rs[0].Summary())
+	assert.Equal(t, 0, rs[0].ShouldTotal().InMinutes())
+	assert.Len(t, rs[0].Entries(), 0)
+}
+
+func TestRoundTripFullBlownRecord(t *testing.T) {
+	original := `2000-12-31 (7h30m!)
+Hello #World
+What’s up?
+    2h3m #some #thing
+    <23:44 - 5:23
+    -45m
+    0:28> - ?
+        Started #todo=nr4
+        still on #it
+`
+	rs, _, pErrs := parser.NewSerialParser().Parse(original)
+	require.Nil(t, pErrs)
+
+	json := ToJson(rs, nil, nil, false)
+	deserialised, err := FromJson(json)
+	require.Nil(t, err)
+	require.Len(t, deserialised, 1)
+	assert.Equal(t, json, ToJson(deserialised, nil, nil, false))
+	assert.Equal(t, klog.NewShouldTotal(7, 30), deserialised[0].ShouldTotal())
+	assert.Equal(t, klog.Ɀ_RecordSummary_("Hello #World", "What’s up?"), deserialised[0].Summary())
+	assert.Equal(t, "0:28>", deserialised[0].OpenRange().Start().ToString())
+}
+
+func TestDeserialiseFailsForInvalidInput(t *testing.T) {
+	for _, x := range []struct {
+		json string
+		msg  string
+	}{
+		{`{"records":[`, "Malformed JSON"},
+		{`{"records":null,"errors":[{"line":1}]}`, "The JSON data contains errors instead of records"},
+		{`{"records":[{"date":"2000-13-01"}]}`, "Record 1 (2000-13-01): Invalid date"},
+		{`{"records":[{"date":"2000-01-01","should_total":"8x!"}]}`, "Record 1 (2000-01-01): Invalid should-total"},
+		{`{"records":[{"date":"2000-01-01","summary":" Foo"}]}`, "Record 1 (2000-01-01): Invalid record summary"},
+		{`{"records":[{"date":"2000-01-01","entries":[{"type":"foo"}]}]}`, "Record 1 (2000-01-01): Entry 1: Unknown entry type `foo`"},
+		{`{"records":[{"date":"2000-01-01","entries":[{"type":"duration","total":"1x"}]}]}`, "Record 1 (2000-01-01): Entry 1: Invalid duration"},
+		{`{"records":[{"date":"2000-01-01","entries":[{"type":"range","start":"10:00","end":"9:00"}]}]}`, "Record 1 (2000-01-01): Entry 1: Start and end time must be in chronological order"},
+		{`{"records":[{"date":"2000-01-01","entries":[{"type":"range","start":"10:00"}]}]}`, "Record 1 (2000-01-01): Entry 1: Invalid start or end time"},
+		{`{"records":[{"date":"2000-01-01","entries":[{"type":"open_range","start":"1:00"},{"type":"open_range","start":"2:00"}]}]}`, "Record 1 (2000-01-01): Entry 2: There can only be one open range per record"},
+	} {
+		t.Run(x.json, func(t *testing.T) {
+			rs, err := FromJson(x.json)
+			require.Error(t, err)
+			assert.Nil(t, rs)
+			assert.Contains(t, err.Error(), x.msg)
+		})
+	}
+}
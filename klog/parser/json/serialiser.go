@@ -1,5 +1,6 @@
 /*
-Package json contains the logic of serialising Record’s as JSON.
+Package json contains the logic of serialising Record’s as JSON, and of
+reading them back from that JSON representation.
 */
 package json
 
@@ -0,0 +1,219 @@
+/*
+Package org contains the logic of converting records from and to the clock
+lines of Emacs org-mode files, e.g.:
+
+	CLOCK: [2024-01-01 Mon 09:00]--[2024-01-01 Mon 10:30] =>  1:30
+
+The clock lines belong to the heading above them, which provides the summary
+text and the tags.
+*/
+package org
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	gotime "time"
+
+	"github.com/jotaen/klog/klog"
+	"github.com/jotaen/klog/klog/parser"
+	"github.com/jotaen/klog/klog/parser/builder"
+)
+
+const timestampLayout = "2006-01-02 Mon 15:04"
+
+// ToOrg serialises records into org-mode headings with clock lines. Every
+// range yields a closed clock, and every open range yields a running clock.
+// The entry summary becomes the heading (or the record summary, if the entry
+// summary is empty). Entries with the same heading are grouped under one
+// heading, in order of their first appearance. Tags from the record summary
+// become org tags, as far as they can be represented as such; otherwise they
+// are appended to the heading. Duration entries are omitted, as they cannot
+// be represented.
+func ToOrg(rs []klog.Record) string {
+	var headings []string
+	clocks := make(map[string][]string)
+	for _, r := range rs {
+		for _, e := range r.Entries() {
+			clock := klog.Unbox(&e, func(tr klog.Range) string {
+				return "CLOCK: " + toTimestamp(r.Date(), tr.Start()) + "--" + toTimestamp(r.Date(), tr.End()) +
+					fmt.Sprintf(" => %2d:%02d", tr.Duration().InMinutes()/60, tr.Duration().InMinutes()%60)
+			}, func(d klog.Duration) string {
+				return ""
+			}, func(o klog.OpenRange) string {
+				return "CLOCK: " + toTimestamp(r.Date(), o.Start())
+			})
+			if clock == "" {
+				continue
+			}
+			heading := toHeading(r, e)
+			if _, exists := clocks[heading]; !exists {
+				headings = append(headings, heading)
+			}
+			clocks[heading] = append(clocks[heading], clock)
+		}
+	}
+	result := ""
+	for _, h := range headings {
+		result += h + "\n"
+		result += "  :LOGBOOK:\n"
+		for _, c := range clocks[h] {
+			result += "  " + c + "\n"
+		}
+		result += "  :END:\n"
+	}
+	return result
+}
+
+func toTimestamp(d klog.Date, t klog.Time) string {
+	midnight := gotime.Date(d.Year(), gotime.Month(d.Month()), d.Day(), 0, 0, 0, 0, gotime.UTC)
+	return "[" + midnight.Add(gotime.Duration(t.MidnightOffset().InMinutes())*gotime.Minute).Format(timestampLayout) + "]"
+}
+
+var orgTagPattern = regexp.MustCompile(`^[\p{L}\p{N}_@#%]+$`)
+
+func toHeading(r klog.Record, e klog.Entry) string {
+	title := strings.Join(parser.SummaryText(e.Summary()), " ")
+	entryTags := e.Summary().Tags()
+	if title == "" {
+		title = strings.Join(parser.SummaryText(r.Summary()), " ")
+		entryTags = r.Summary().Tags()
+	}
+	var orgTags []string
+	for _, t := range r.Summary().Tags().ToStrings() {
+		tag, _ := klog.NewTagFromString(t)
+		if entryTags.Contains(tag) {
+			continue
+		}
+		entryTags.Put(tag)
+		if tag.Value() == "" && orgTagPattern.MatchString(tag.Name()) {
+			orgTags = append(orgTags, tag.Name())
+		} else {
+			title = strings.TrimSpace(title + " " + tag.ToString())
+		}
+	}
+	heading := "* " + title
+	if len(orgTags) > 0 {
+		heading += " :" + strings.Join(orgTags, ":") + ":"
+	}
+	return heading
+}
+
+// FromOrg reads the clock lines from org-mode text and converts them to
+// records. Every closed clock yields a range, and every running clock yields
+// an open range, in the record at the clock’s start date. The title of the
+// heading that the clock line belongs to becomes the summary text (without
+// todo keyword and priority), and the org tags of the heading (including
+// the inherited ones) become tags. All other content is ignored.
+// The resulting records are sorted by date.
+func FromOrg(text string) ([]klog.Record, error) {
+	b := builder.NewRecordBuilder()
+	todoKeywords := map[string]bool{"TODO": true, "DONE": true}
+	var outline []heading
+	for i, line := range strings.Split(text, "\n") {
+		line = strings.TrimRight(line, "\r")
+		if match := todoKeywordsPattern.FindStringSubmatch(line); match != nil {
+			for _, k := range strings.Fields(match[1]) {
+				k, _, _ = strings.Cut(k, "(")
+				todoKeywords[k] = true
+			}
+			continue
+		}
+		if match := headingPattern.FindStringSubmatch(line); match != nil {
+			h := newHeading(len(match[1]), match[2], todoKeywords)
+			for len(outline) > 0 && outline[len(outline)-1].level >= h.level {
+				outline = outline[:len(outline)-1]
+			}
+			outline = append(outline, h)
+			continue
+		}
+		if !strings.HasPrefix(strings.TrimSpace(line), "CLOCK:") {
+			continue
+		}
+		err := addClock(b, line, outline)
+		if err != nil {
+			return nil, fmt.Errorf("Line %d: %w", i+1, err)
+		}
+	}
+	return b.Records(), nil
+}
+
+type heading struct {
+	level int
+	title string
+	tags  []string
+}
+
+var todoKeywordsPattern = regexp.MustCompile(`^#\+(?:SEQ_|TYP_)?TODO:(.*)$`)
+var headingPattern = regexp.MustCompile(`^(\*+)(?:[ \t]+(.*))?$`)
+var headingTagsPattern = regexp.MustCompile(`[ \t]+:((?:[^\s:]+:)+)$`)
+var priorityPattern = regexp.MustCompile(`^\[#[A-Z0-9]\][ \t]*`)
+
+func newHeading(level int, text string, todoKeywords map[string]bool) heading {
+	h := heading{level: level}
+	text = strings.TrimSpace(text)
+	if match := headingTagsPattern.FindStringSubmatchIndex(" " + text); match != nil {
+		h.tags = strings.Split(strings.Trim((" " + text)[match[2]:match[3]], ":"), ":")
+		text = strings.TrimSpace((" " + text)[:match[0]])
+	}
+	keyword, rest, _ := strings.Cut(text, " ")
+	if todoKeywords[keyword] {
+		text = strings.TrimSpace(rest)
+	}
+	h.title = priorityPattern.ReplaceAllString(text, "")
+	return h
+}
+
+var clockPattern = regexp.MustCompile(`^\s*CLOCK:\s*[\[<]([^\]>]+)[\]>](?:--[\[<]([^\]>]+)[\]>](?:\s*=>\s*-?\d+:\d{2})?)?\s*$`)
+var timestampPattern = regexp.MustCompile(`^(\d{4}-\d{2}-\d{2})(?:\s+[^\d\s]+)?\s+(\d{1,2}:\d{2})$`)
+
+func addClock(b *builder.RecordBuilder, line string, outline []heading) error {
+	match := clockPattern.FindStringSubmatch(line)
+	if match == nil {
+		return errors.New("Malformed clock line")
+	}
+	start, sErr := parseTimestamp(match[1])
+	if sErr != nil {
+		return errors.New("Invalid start time")
+	}
+	var tags []klog.Tag
+	for _, h := range outline {
+		for _, t := range h.tags {
+			if t == "" {
+				continue
+			}
+			tag, tErr := builder.NewTag(t)
+			if tErr != nil {
+				return tErr
+			}
+			tags = append(tags, tag)
+		}
+	}
+	title := ""
+	if len(outline) > 0 {
+		title = outline[len(outline)-1].title
+	}
+	summary, suErr := builder.NewSummary(title, tags)
+	if suErr != nil {
+		return errors.New("Invalid heading")
+	}
+	if match[2] == "" {
+		return b.AddOpenRange(start, summary)
+	}
+	end, eErr := parseTimestamp(match[2])
+	if eErr != nil {
+		return errors.New("Invalid end time")
+	}
+	return b.AddRange(start, end, summary)
+}
+
+// parseTimestamp parses an org-mode timestamp (without the brackets), e.g.
+// `2024-01-01 Mon 09:00`. The day name is optional and not validated.
+func parseTimestamp(value string) (gotime.Time, error) {
+	match := timestampPattern.FindStringSubmatch(strings.TrimSpace(value))
+	if match == nil {
+		return gotime.Time{}, errors.New("Invalid timestamp")
+	}
+	return gotime.Parse("2006-01-02 15:04", match[1]+" "+match[2])
+}
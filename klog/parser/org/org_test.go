@@ -0,0 +1,126 @@
+package org
+
+import (
+	"testing"
+
+	"github.com/jotaen/klog/klog"
+	"github.com/jotaen/klog/klog/parser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func entryTexts(r klog.Record) []string {
+	var result []string
+	for _, e := range r.Entries() {
+		value := klog.Unbox(&e,
+			func(tr klog.Range) string { return tr.ToString() },
+			func(d klog.Duration) string { return d.ToString() },
+			func(o klog.OpenRange) string { return o.ToString() },
+		)
+		summary := parser.SummaryText(e.Summary()).ToString()
+		if summary != "" {
+			value += " " + summary
+		}
+		result = append(result, value)
+	}
+	return result
+}
+
+func TestSerialiseClocks(t *testing.T) {
+	text := ToOrg(func() []klog.Record {
+		r1 := klog.NewRecord(klog.Ɀ_Date_(2024, 1, 1))
+		r1.SetSummary(klog.Ɀ_RecordSummary_("Some day #acme #ticket=123"))
+		r1.AddRange(klog.Ɀ_Range_(klog.Ɀ_TimeYesterday_(23, 0), klog.Ɀ_Time_(1, 30)), klog.Ɀ_EntrySummary_("Write report"))
+		r1.AddDuration(klog.NewDuration(1, 0), klog.Ɀ_EntrySummary_("Omitted"))
+		r1.AddRange(klog.Ɀ_Range_(klog.Ɀ_Time_(9, 0), klog.Ɀ_Time_(10, 15)), klog.Ɀ_EntrySummary_("Review #acme"))
+		r2 := klog.NewRecord(klog.Ɀ_Date_(2024, 1, 2))
+		r2.AddRange(klog.Ɀ_Range_(klog.Ɀ_Time_(8, 0), klog.Ɀ_TimeTomorrow_(2, 0)), klog.Ɀ_EntrySummary_("Review #acme"))
+		r2.Start(klog.NewOpenRange(klog.Ɀ_Time_(11, 0)), nil)
+		return []klog.Record{r1, r2}
+	}())
+	assert.Equal(t, `* Write report #ticket=123 :acme:
+  :LOGBOOK:
+  CLOCK: [2023-12-31 Sun 23:00]--[2024-01-01 Mon 01:30] =>  2:30
+  :END:
+* Review #acme #ticket=123
+  :LOGBOOK:
+  CLOCK: [2024-01-01 Mon 09:00]--[2024-01-01 Mon 10:15] =>  1:15
+  :END:
+* Review #acme
+  :LOGBOOK:
+  CLOCK: [2024-01-02 Tue 08:00]--[2024-01-03 Wed 02:00] => 18:00
+  :END:
+* 
+  :LOGBOOK:
+  CLOCK: [2024-01-02 Tue 11:00]
+  :END:
+`, text)
+}
+
+func TestDeserialiseClocks(t *testing.T) {
+	rs, err := FromOrg(`#+TITLE: Work
+#+TODO: NEXT(n) WAIT | DONE CANCELLED
+
+* Project Alpha                                            :acme:
+Some notes, which are ignored.
+** DONE [#A] Write report
+   :LOGBOOK:
+   CLOCK: [2024-01-01 Mon 09:00]--[2024-01-01 Mon 10:30] =>  1:30
+   CLOCK: [2024-01-01 Mon 23:00]--[2024-01-02 Tue 00:15] =>  1:15
+   :END:
+** NEXT Call client :phone:call_2:
+   CLOCK: [2024-01-02 Tue 14:00]
+* Other
+  CLOCK: [2024-01-02 14:00]--[2024-01-02 15:00]
+`)
+	require.Nil(t, err)
+	require.Len(t, rs, 2)
+	assert.Equal(t, "2024-01-01", rs[0].Date().ToString())
+	assert.Equal(t, []string{
+		"9:00 - 10:30 Write report #acme",
+		"23:00 - 0:15> Write report #acme",
+	}, entryTexts(rs[0]))
+	assert.Equal(t, "2024-01-02", rs[1].Date().ToString())
+	assert.Equal(t, []string{
+		"14:00 - ? Call client #acme #phone #call_2",
+		"14:00 - 15:00 Other",
+	}, entryTexts(rs[1]))
+}
+
+func TestDeserialiseClocksWithoutHeading(t *testing.T) {
+	rs, err := FromOrg("CLOCK: [2024-01-01 Mon 09:00]--[2024-01-01 Mon 10:00] =>  1:00\r\n")
+	require.Nil(t, err)
+	require.Len(t, rs, 1)
+	assert.Equal(t, []string{"9:00 - 10:00"}, entryTexts(rs[0]))
+}
+
+func TestRoundTrip(t *testing.T) {
+	rs, err := FromOrg(ToOrg(func() []klog.Record {
+		r := klog.NewRecord(klog.Ɀ_Date_(2024, 1, 1))
+		r.SetSummary(klog.Ɀ_RecordSummary_("#acme"))
+		r.AddRange(klog.Ɀ_Range_(klog.Ɀ_Time_(9, 0), klog.Ɀ_Time_(10, 0)), klog.Ɀ_EntrySummary_("Coding #go"))
+		return []klog.Record{r}
+	}()))
+	require.Nil(t, err)
+	require.Len(t, rs, 1)
+	assert.Equal(t, []string{"9:00 - 10:00 Coding #go #acme"}, entryTexts(rs[0]))
+}
+
+func TestDeserialiseRejectsInvalidClocks(t *testing.T) {
+	for _, x := range []struct {
+		text string
+		err  string
+	}{
+		{"* A\nCLOCK: 2024-01-01 09:00", "Line 2: Malformed clock line"},
+		{"CLOCK: [2024-13-01 Mon 09:00]", "Line 1: Invalid start time"},
+		{"CLOCK: [2024-01-01 Mon 09:00]--[2024-01-01 Mon 25:00]", "Line 1: Invalid end time"},
+		{"CLOCK: [2024-01-01 Mon 09:00]--[2024-01-01 Mon 08:00]", "Line 1: Start and end time must be in chronological order"},
+		{"CLOCK: [2024-01-01 Mon 09:00]--[2024-01-05 Fri 08:00]", "Line 1: The time span must not exceed two days"},
+		{"CLOCK: [2024-01-01 Mon 09:00]\nCLOCK: [2024-01-01 Mon 10:00]", "Line 2: There can only be one open range per record"},
+	} {
+		rs, err := FromOrg(x.text)
+		require.Error(t, err, x.text)
+		assert.Nil(t, rs)
+		assert.Equal(t, x.err, err.Error())
+	}
+}
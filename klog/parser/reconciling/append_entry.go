@@ -1,6 +1,10 @@
 package reconciling
 
-import "github.com/jotaen/klog/klog"
+import (
+	"errors"
+
+	"github.com/jotaen/klog/klog"
+)
 
 // AppendEntry adds a new entry to the end of the record.
 // `newEntry` must include the entry value at the beginning of its first line.
@@ -8,3 +12,64 @@ func (r *Reconciler) AppendEntry(newEntry klog.EntrySummary) error {
 	r.insert(r.lastLinePointer, toMultilineEntryTexts("", newEntry))
 	return nil
 }
+
+// AppendEntryFrom adds a copy of an existing entry (including its summary) to the
+// end of the record. The time values of ranges and open ranges are reformatted
+// according to the format directive. In contrast to `AppendEntry`, it can be
+// invoked multiple times on the same reconciler.
+func (r *Reconciler) AppendEntryFrom(e klog.Entry, format ReformatDirective[klog.TimeFormat]) error {
+	var err error
+	reformat := func(t klog.Time) klog.Time {
+		format.apply(r.style.timeFormat(), func(f klog.TimeFormat) {
+			// Re-parse time to apply format.
+			reformattedTime, tErr := klog.NewTimeFromString(t.ToStringWithFormat(f))
+			if tErr != nil {
+				err = errors.New("Invalid time: " + t.ToString())
+				return
+			}
+			t = reformattedTime
+		})
+		return t
+	}
+	newEntry := klog.Unbox[klog.Entry](&e, func(tr klog.Range) klog.Entry {
+		rangeFormat := tr.Format()
+		format.apply(r.style.timeFormat(), func(_ klog.TimeFormat) {
+			rangeFormat.UseSpacesAroundDash = r.style.rangesUseSpacesAroundDash.Get()
+		})
+		start, end := reformat(tr.Start()), reformat(tr.End())
+		if err != nil {
+			return e
+		}
+		newRange, rErr := klog.NewRangeWithFormat(start, end, rangeFormat)
+		if rErr != nil {
+			err = errors.New("Start and end time must be in chronological order")
+			return e
+		}
+		return klog.NewEntryFromRange(newRange, e.Summary())
+	}, func(d klog.Duration) klog.Entry {
+		return e
+	}, func(o klog.OpenRange) klog.Entry {
+		if r.findOpenRangeIndex() != -1 {
+			err = errors.New("There is already an open range in this record")
+			return e
+		}
+		start := reformat(o.Start())
+		if err != nil {
+			return e
+		}
+		return klog.NewEntryFromOpenRange(klog.NewOpenRangeWithFormat(start, r.style.openRangeFormat()), e.Summary())
+	})
+	if err != nil {
+		return err
+	}
+	entryValue := klog.Unbox[string](&newEntry,
+		func(tr klog.Range) string { return tr.ToString() },
+		func(d klog.Duration) string { return d.ToString() },
+		func(o klog.OpenRange) string { return o.ToString() },
+	)
+	texts := toMultilineEntryTexts(entryValue, e.Summary())
+	r.insert(r.lastLinePointer, texts)
+	r.lastLinePointer += len(texts)
+	r.Record.SetEntries(append(r.Record.Entries(), newEntry))
+	return nil
+}
@@ -138,3 +138,104 @@ func TestReconcilerRejectsInvalidEntry(t *testing.T) {
 	assert.Error(t, rErr)
 	assert.Nil(t, result)
 }
+
+func TestReconcilerAppendsMultipleExistingEntries(t *testing.T) {
+	original := `
+2018-01-01
+    1h
+
+2018-01-02
+    5h
+`
+	rs, bs, _ := parser.NewSerialParser().Parse(original)
+	reconciler := NewReconcilerAtRecord(klog.Ɀ_Date_(2018, 1, 1))(rs, bs)
+	require.NotNil(t, reconciler)
+
+	for _, e := range []klog.Entry{
+		klog.NewEntryFromDuration(klog.NewDuration(-0, -30), klog.Ɀ_EntrySummary_("Lunch")),
+		klog.NewEntryFromRange(klog.Ɀ_NoSpaces_(klog.Ɀ_Range_(klog.Ɀ_TimeYesterday_(23, 0), klog.Ɀ_Time_(1, 30))), klog.Ɀ_EntrySummary_("", "#night")),
+		klog.NewEntryFromOpenRange(klog.NewOpenRange(klog.Ɀ_IsAmPm_(klog.Ɀ_Time_(15, 0))), nil),
+	} {
+		err := reconciler.AppendEntryFrom(e, NoReformat[klog.TimeFormat]())
+		require.Nil(t, err)
+	}
+
+	result := assertResult(t, reconciler)
+	assert.Equal(t, `
+2018-01-01
+    1h
+    -30m Lunch
+    <23:00-1:30
+        #night
+    3:00pm - ?
+
+2018-01-02
+    5h
+`, result.AllSerialised)
+	assert.Len(t, result.Record.Entries(), 4)
+}
+
+func TestReconcilerAppendsExistingEntryWithReformatting(t *testing.T) {
+	original := `
+2018-01-01
+    9:00am-10:00am
+`
+	rs, bs, _ := parser.NewSerialParser().Parse(original)
+	reconciler := NewReconcilerAtRecord(klog.Ɀ_Date_(2018, 1, 1))(rs, bs)
+	require.NotNil(t, reconciler)
+
+	err := reconciler.AppendEntryFrom(
+		klog.NewEntryFromRange(klog.Ɀ_Range_(klog.Ɀ_Time_(13, 0), klog.Ɀ_Time_(14, 15)), nil),
+		ReformatAutoStyle[klog.TimeFormat](),
+	)
+	require.Nil(t, err)
+
+	result := assertResult(t, reconciler)
+	assert.Equal(t, `
+2018-01-01
+    9:00am-10:00am
+    1:00pm-2:15pm
+`, result.AllSerialised)
+}
+
+func TestReconcilerRejectsSecondOpenRangeFromExistingEntry(t *testing.T) {
+	original := `
+2018-01-01
+    9:00 - ?
+`
+	rs, bs, _ := parser.NewSerialParser().Parse(original)
+	reconciler := NewReconcilerAtRecord(klog.Ɀ_Date_(2018, 1, 1))(rs, bs)
+	require.NotNil(t, reconciler)
+
+	err := reconciler.AppendEntryFrom(
+		klog.NewEntryFromOpenRange(klog.NewOpenRange(klog.Ɀ_Time_(13, 0)), nil),
+		NoReformat[klog.TimeFormat](),
+	)
+	require.Error(t, err)
+}
+
+type unserialisableTime struct {
+	klog.Time
+}
+
+func (t unserialisableTime) ToStringWithFormat(_ klog.TimeFormat) string {
+	return "25:00"
+}
+
+func TestReconcilerRejectsExistingEntryWithInvalidTime(t *testing.T) {
+	original := `
+2018-01-01
+    9:00 - 10:00
+`
+	rs, bs, _ := parser.NewSerialParser().Parse(original)
+	reconciler := NewReconcilerAtRecord(klog.Ɀ_Date_(2018, 1, 1))(rs, bs)
+	require.NotNil(t, reconciler)
+
+	for _, e := range []klog.Entry{
+		klog.NewEntryFromRange(klog.Ɀ_Range_(unserialisableTime{klog.Ɀ_Time_(13, 0)}, klog.Ɀ_Time_(14, 0)), nil),
+		klog.NewEntryFromOpenRange(klog.NewOpenRange(unserialisableTime{klog.Ɀ_Time_(13, 0)}), nil),
+	} {
+		err := reconciler.AppendEntryFrom(e, ReformatAutoStyle[klog.TimeFormat]())
+		require.Error(t, err)
+	}
+}
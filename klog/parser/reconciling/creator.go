@@ -45,16 +45,18 @@ func NewReconcilerForNewRecord(atDate klog.Date, format ReformatDirective[klog.D
 		for _, s := range ad.Summary {
 			recordText = append(recordText, insertableText{s, 0})
 		}
+		// The last line pointer must point behind the headline and the record summary.
+		recordLinesCount := len(recordText)
 		newRecordLines, insertPointer, lastLineOffset, newRecordIndex := func() ([]insertableText, int, int, int) {
 			if len(rs) == 0 {
-				return recordText, 0, 1, 0
+				return recordText, 0, recordLinesCount, 0
 			}
 			i := 0
 			for _, r := range rs {
 				if i == 0 && !atDate.IsAfterOrEqual(r.Date()) {
 					// The new record is dated prior to the first one, so we have to append a blank line.
 					recordText = append(recordText, blankLine)
-					return recordText, 0, 1, 0
+					return recordText, 0, recordLinesCount, 0
 				}
 				if len(rs)-1 == i || (atDate.IsAfterOrEqual(r.Date()) && !atDate.IsAfterOrEqual(rs[i+1].Date())) {
 					// The record is in between.
@@ -64,7 +66,7 @@ func NewReconcilerForNewRecord(atDate klog.Date, format ReformatDirective[klog.D
 			}
 			// The new record is dated after the last one, so we have to prepend a blank line.
 			recordText = append([]insertableText{blankLine}, recordText...)
-			return recordText, indexOfLastSignificantLine(bs[i]), 2, i + 1
+			return recordText, indexOfLastSignificantLine(bs[i]), 1 + recordLinesCount, i + 1
 		}()
 
 		// Insert record and adjust pointers accordingly.
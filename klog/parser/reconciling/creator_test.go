@@ -212,6 +212,28 @@ func TestReconcileAddRecordWithUTF8Summary(t *testing.T) {
 	assert.Equal(t, result.Record.Summary(), summary)
 }
 
+func TestReconcileAddRecordWithSummaryAndEntries(t *testing.T) {
+	for _, x := range []struct {
+		original string
+		expected string
+	}{
+		{"", "2018-01-02\nFirst line\nSecond line\n    1h\n"},
+		{"2018-01-03\n", "2018-01-02\nFirst line\nSecond line\n    1h\n\n2018-01-03\n"},
+		{"2018-01-01\n", "2018-01-01\n\n2018-01-02\nFirst line\nSecond line\n    1h\n"},
+		{"2018-01-01\n\n2018-01-03\n", "2018-01-01\n\n2018-01-02\nFirst line\nSecond line\n    1h\n\n2018-01-03\n"},
+	} {
+		rs, bs, _ := parser.NewSerialParser().Parse(x.original)
+		atDate := klog.Ɀ_Date_(2018, 1, 2)
+		summary := klog.Ɀ_RecordSummary_("First line", "Second line")
+		reconciler := NewReconcilerForNewRecord(atDate, NoReformat[klog.DateFormat](), AdditionalData{Summary: summary})(rs, bs)
+		err := reconciler.AppendEntryFrom(klog.NewEntryFromDuration(klog.NewDuration(1, 0), nil), NoReformat[klog.TimeFormat]())
+		require.Nil(t, err)
+		result, err := reconciler.MakeResult()
+		require.Nil(t, err)
+		assert.Equal(t, x.expected, result.AllSerialised)
+	}
+}
+
 func TestReconcileDetectsExistingStylePref(t *testing.T) {
 	for _, x := range []struct {
 		original string
@@ -0,0 +1,112 @@
+/*
+Package timeclock contains the logic of converting records to the timeclock
+format, as understood by accounting tools such as hledger or ledger, e.g.:
+
+	i 2024-01-01 09:00:00 acme:backend  Fix bug
+	o 2024-01-01 10:30:00
+*/
+package timeclock
+
+import (
+	"strings"
+	gotime "time"
+
+	"github.com/jotaen/klog/klog"
+	"github.com/jotaen/klog/klog/parser"
+)
+
+const timestampLayout = "2006-01-02 15:04:05"
+
+// DefaultAccount is the account name for entries without a matching tag.
+const DefaultAccount = "untagged"
+
+// ToTimeclock serialises records into timeclock check-in/check-out pairs.
+// Every range yields one pair. Every duration entry yields a pair as well,
+// which starts where the latest range of that record ended (or at midnight
+// of the record date, if there are no ranges), or where the preceding
+// duration entry of that record ended. That way, the pairs don’t overlap.
+// Negative durations are omitted, as are open ranges.
+// The account name is derived from the tag with the name `accountTag`; if
+// `accountTag` is empty, it’s the first tag of the entry (or of the record).
+// The levels of hierarchical tags and tag values become sub-accounts, e.g.
+// `#acme/backend` yields `acme:backend`, and `#client=acme` yields `client:acme`.
+// The entry summary becomes the description.
+func ToTimeclock(rs []klog.Record, accountTag string) string {
+	accountTag = strings.ToLower(strings.TrimPrefix(accountTag, "#"))
+	result := ""
+	for _, r := range rs {
+		midnight := gotime.Date(r.Date().Year(), gotime.Month(r.Date().Month()), r.Date().Day(), 0, 0, 0, 0, gotime.UTC)
+		durationsEnd := latestRangeEnd(r, midnight)
+		for _, e := range r.Entries() {
+			var start, end gotime.Time
+			isValid := klog.Unbox(&e, func(tr klog.Range) bool {
+				start = midnight.Add(toGoDuration(tr.Start().MidnightOffset()))
+				end = midnight.Add(toGoDuration(tr.End().MidnightOffset()))
+				return true
+			}, func(d klog.Duration) bool {
+				if d.InMinutes() < 0 {
+					return false
+				}
+				start = durationsEnd
+				end = start.Add(toGoDuration(d))
+				durationsEnd = end
+				return true
+			}, func(o klog.OpenRange) bool {
+				return false
+			})
+			if !isValid {
+				continue
+			}
+			checkIn := "i " + start.Format(timestampLayout) + " " + toAccount(r, e, accountTag)
+			description := strings.Join(strings.Fields(strings.Join(parser.SummaryText(e.Summary()), " ")), " ")
+			if description != "" {
+				checkIn += "  " + description
+			}
+			result += checkIn + "\n"
+			result += "o " + end.Format(timestampLayout) + "\n"
+		}
+	}
+	return result
+}
+
+// latestRangeEnd returns the end of the latest range of the record, or
+// midnight, if there are no ranges.
+func latestRangeEnd(r klog.Record, midnight gotime.Time) gotime.Time {
+	result := midnight
+	for _, e := range r.Entries() {
+		end := klog.Unbox(&e, func(tr klog.Range) gotime.Time {
+			return midnight.Add(toGoDuration(tr.End().MidnightOffset()))
+		}, func(klog.Duration) gotime.Time {
+			return midnight
+		}, func(klog.OpenRange) gotime.Time {
+			return midnight
+		})
+		if end.After(result) {
+			result = end
+		}
+	}
+	return result
+}
+
+func toGoDuration(d klog.Duration) gotime.Duration {
+	return gotime.Duration(d.InMinutes()) * gotime.Minute
+}
+
+// toAccount determines the account name from the tags of the entry and the
+// record (in this order).
+func toAccount(r klog.Record, e klog.Entry, accountTag string) string {
+	for _, t := range append(e.Summary().Tags().ToStrings(), r.Summary().Tags().ToStrings()...) {
+		tag, _ := klog.NewTagFromString(t)
+		if accountTag != "" && tag.Name() != accountTag {
+			continue
+		}
+		account := strings.ReplaceAll(tag.Name(), klog.TagSeparator, ":")
+		if tag.Value() != "" {
+			account += ":" + tag.Value()
+		}
+		// Account names must not contain consecutive whitespace, since that
+		// separates the account name from the description.
+		return strings.Join(strings.Fields(account), " ")
+	}
+	return DefaultAccount
+}
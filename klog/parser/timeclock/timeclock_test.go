@@ -0,0 +1,76 @@
+package timeclock
+
+import (
+	"testing"
+
+	"github.com/jotaen/klog/klog"
+	"github.com/stretchr/testify/assert"
+)
+
+func records() []klog.Record {
+	r1 := klog.NewRecord(klog.Ɀ_Date_(2024, 1, 1))
+	r1.SetSummary(klog.Ɀ_RecordSummary_("#client=acme"))
+	r1.AddRange(klog.Ɀ_Range_(klog.Ɀ_TimeYesterday_(23, 0), klog.Ɀ_Time_(1, 30)), klog.Ɀ_EntrySummary_("Deploy #ops", "at night"))
+	r1.AddDuration(klog.NewDuration(1, 30), klog.Ɀ_EntrySummary_("Meeting #project=alpha"))
+	r1.AddDuration(klog.NewDuration(0, 45), nil)
+	r1.AddDuration(klog.NewDuration(-1, 0), klog.Ɀ_EntrySummary_("Omitted"))
+	r2 := klog.NewRecord(klog.Ɀ_Date_(2024, 1, 2))
+	r2.AddRange(klog.Ɀ_Range_(klog.Ɀ_Time_(8, 0), klog.Ɀ_Time_(9, 0)), klog.Ɀ_EntrySummary_(`#project="Big  thing"`))
+	r2.AddRange(klog.Ɀ_Range_(klog.Ɀ_Time_(10, 0), klog.Ɀ_Time_(11, 0)), nil)
+	r2.Start(klog.NewOpenRange(klog.Ɀ_Time_(12, 0)), klog.Ɀ_EntrySummary_("Omitted"))
+	return []klog.Record{r1, r2}
+}
+
+func TestSerialiseWithFirstTagAsAccount(t *testing.T) {
+	text := ToTimeclock(records(), "")
+	assert.Equal(t, `i 2023-12-31 23:00:00 ops  Deploy #ops at night
+o 2024-01-01 01:30:00
+i 2024-01-01 01:30:00 project:alpha  Meeting #project=alpha
+o 2024-01-01 03:00:00
+i 2024-01-01 03:00:00 client:acme
+o 2024-01-01 03:45:00
+i 2024-01-02 08:00:00 project:Big thing  #project="Big thing"
+o 2024-01-02 09:00:00
+i 2024-01-02 10:00:00 untagged
+o 2024-01-02 11:00:00
+`, text)
+}
+
+func TestSerialiseWithConfiguredTagAsAccount(t *testing.T) {
+	text := ToTimeclock(records(), "#Client")
+	assert.Equal(t, `i 2023-12-31 23:00:00 client:acme  Deploy #ops at night
+o 2024-01-01 01:30:00
+i 2024-01-01 01:30:00 client:acme  Meeting #project=alpha
+o 2024-01-01 03:00:00
+i 2024-01-01 03:00:00 client:acme
+o 2024-01-01 03:45:00
+i 2024-01-02 08:00:00 untagged  #project="Big thing"
+o 2024-01-02 09:00:00
+i 2024-01-02 10:00:00 untagged
+o 2024-01-02 11:00:00
+`, text)
+}
+
+func TestSerialiseDurationsAfterLatestRange(t *testing.T) {
+	r := klog.NewRecord(klog.Ɀ_Date_(2024, 1, 1))
+	r.AddDuration(klog.NewDuration(1, 0), nil)
+	r.AddRange(klog.Ɀ_Range_(klog.Ɀ_Time_(13, 0), klog.Ɀ_Time_(14, 0)), nil)
+	r.AddRange(klog.Ɀ_Range_(klog.Ɀ_Time_(8, 0), klog.Ɀ_Time_(9, 0)), nil)
+	text := ToTimeclock([]klog.Record{r}, "")
+	assert.Equal(t, `i 2024-01-01 14:00:00 untagged
+o 2024-01-01 15:00:00
+i 2024-01-01 13:00:00 untagged
+o 2024-01-01 14:00:00
+i 2024-01-01 08:00:00 untagged
+o 2024-01-01 09:00:00
+`, text)
+}
+
+func TestSerialiseHierarchicalTagsAsSubAccounts(t *testing.T) {
+	r := klog.NewRecord(klog.Ɀ_Date_(2024, 1, 1))
+	r.AddRange(klog.Ɀ_Range_(klog.Ɀ_Time_(8, 0), klog.Ɀ_Time_(9, 0)), klog.Ɀ_EntrySummary_("#acme/backend=api"))
+	text := ToTimeclock([]klog.Record{r}, "acme/backend")
+	assert.Equal(t, `i 2024-01-01 08:00:00 acme:backend:api  #acme/backend=api
+o 2024-01-01 09:00:00
+`, text)
+}
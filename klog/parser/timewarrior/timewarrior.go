@@ -0,0 +1,234 @@
+/*
+Package timewarrior contains the logic of converting records from and to the
+interval format of Timewarrior’s data files, e.g.:
+
+	inc 20240101T080000Z - 20240101T093000Z # meeting "client x" # "Kick-off"
+*/
+package timewarrior
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	gotime "time"
+
+	"github.com/jotaen/klog/klog"
+	"github.com/jotaen/klog/klog/parser"
+	"github.com/jotaen/klog/klog/parser/builder"
+)
+
+const timestampLayout = "20060102T150405Z"
+
+// ToTimewarrior serialises records into Timewarrior intervals, one per line.
+// Every range yields a closed interval, and every open range yields an open
+// interval. The tags of the entry become the interval tags, and the entry
+// summary becomes the interval annotation. Duration entries are omitted,
+// as they cannot be represented. The times are converted from `location`
+// to UTC, which is how Timewarrior stores them.
+func ToTimewarrior(rs []klog.Record, location *gotime.Location) string {
+	result := ""
+	for _, r := range rs {
+		for _, e := range r.Entries() {
+			interval := klog.Unbox(&e, func(tr klog.Range) string {
+				return "inc " + toTimestamp(r.Date(), tr.Start(), location) + " - " + toTimestamp(r.Date(), tr.End(), location)
+			}, func(d klog.Duration) string {
+				return ""
+			}, func(o klog.OpenRange) string {
+				return "inc " + toTimestamp(r.Date(), o.Start(), location)
+			})
+			if interval == "" {
+				continue
+			}
+			tags := toTags(r, e)
+			annotation := strings.Join(parser.SummaryText(e.Summary()), " ")
+			if len(tags) > 0 || annotation != "" {
+				interval += " #"
+				for _, t := range tags {
+					interval += " " + quoteIfNeeded(t)
+				}
+			}
+			if annotation != "" {
+				interval += ` # "` + strings.ReplaceAll(annotation, `"`, `\"`) + `"`
+			}
+			result += interval + "\n"
+		}
+	}
+	return result
+}
+
+func toTimestamp(d klog.Date, t klog.Time, location *gotime.Location) string {
+	day := d.Day()
+	if t.IsYesterday() {
+		day -= 1
+	} else if t.IsTomorrow() {
+		day += 1
+	}
+	// `gotime.Date` normalises days that are out of range, e.g. the 0th of a month.
+	return gotime.Date(d.Year(), gotime.Month(d.Month()), day, t.Hour(), t.Minute(), 0, 0, location).UTC().Format(timestampLayout)
+}
+
+// toTags returns all tags that apply to the entry (without the leading `#`),
+// i.e. the ones from the record summary and the ones from the entry summary.
+func toTags(r klog.Record, e klog.Entry) []string {
+	var result []string
+	seen := make(map[string]bool)
+	for _, t := range append(r.Summary().Tags().ToStrings(), e.Summary().Tags().ToStrings()...) {
+		tag, _ := klog.NewTagFromString(t)
+		name := tag.Name()
+		if tag.Value() != "" {
+			name += "=" + tag.Value()
+		}
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+		result = append(result, name)
+	}
+	sort.Strings(result)
+	return result
+}
+
+func quoteIfNeeded(text string) string {
+	if text != "" && !strings.ContainsAny(text, " \t\"#") {
+		return text
+	}
+	return `"` + strings.ReplaceAll(text, `"`, `\"`) + `"`
+}
+
+// FromTimewarrior reads Timewarrior intervals and converts them to records.
+// Every interval yields a range (or an open range, if the interval is open)
+// in the record at the interval’s start date. The interval tags become tags
+// in the entry summary, and the annotation becomes the summary text.
+// The times are converted from UTC to `location`. Empty lines are ignored.
+// The resulting records are sorted by date.
+func FromTimewarrior(text string, location *gotime.Location) ([]klog.Record, error) {
+	b := builder.NewRecordBuilder()
+	for i, line := range strings.Split(text, "\n") {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		err := addInterval(b, line, location)
+		if err != nil {
+			return nil, fmt.Errorf("Line %d: %w", i+1, err)
+		}
+	}
+	return b.Records(), nil
+}
+
+type token struct {
+	value    string
+	isQuoted bool
+}
+
+func (t token) is(value string) bool {
+	return !t.isQuoted && t.value == value
+}
+
+func addInterval(b *builder.RecordBuilder, line string, location *gotime.Location) error {
+	tokens, err := tokenise(line)
+	if err != nil {
+		return err
+	}
+	if len(tokens) < 2 || !tokens[0].is("inc") {
+		return errors.New("Malformed interval")
+	}
+	start, sErr := gotime.Parse(timestampLayout, tokens[1].value)
+	if sErr != nil {
+		return errors.New("Invalid start time")
+	}
+	tokens = tokens[2:]
+
+	var end *gotime.Time
+	if len(tokens) > 0 && tokens[0].is("-") {
+		if len(tokens) < 2 {
+			return errors.New("Missing end time")
+		}
+		e, eErr := gotime.Parse(timestampLayout, tokens[1].value)
+		if eErr != nil {
+			return errors.New("Invalid end time")
+		}
+		end = &e
+		tokens = tokens[2:]
+	}
+
+	var tags []klog.Tag
+	annotation := ""
+	if len(tokens) > 0 {
+		if !tokens[0].is("#") {
+			return errors.New("Malformed interval")
+		}
+		tokens = tokens[1:]
+		for i, t := range tokens {
+			if t.is("#") {
+				var annotationParts []string
+				for _, a := range tokens[i+1:] {
+					annotationParts = append(annotationParts, a.value)
+				}
+				annotation = strings.Join(annotationParts, " ")
+				break
+			}
+			tag, tErr := builder.NewTag(t.value)
+			if tErr != nil {
+				return tErr
+			}
+			tags = append(tags, tag)
+		}
+	}
+
+	summary, suErr := builder.NewSummary(annotation, tags)
+	if suErr != nil {
+		return errors.New("Invalid annotation")
+	}
+	if end == nil {
+		return b.AddOpenRange(start.In(location), summary)
+	}
+	return b.AddRange(start.In(location), end.In(location), summary)
+}
+
+// tokenise splits up the line at whitespace, whereby it respects double
+// quotes (with backslash escapes).
+func tokenise(line string) ([]token, error) {
+	var tokens []token
+	var current *token
+	isInQuotes := false
+	isEscaped := false
+	for _, c := range line {
+		if isInQuotes {
+			if isEscaped {
+				current.value += string(c)
+				isEscaped = false
+			} else if c == '\\' {
+				isEscaped = true
+			} else if c == '"' {
+				isInQuotes = false
+			} else {
+				current.value += string(c)
+			}
+			continue
+		}
+		if c == ' ' || c == '\t' || c == '\r' {
+			if current != nil {
+				tokens = append(tokens, *current)
+				current = nil
+			}
+			continue
+		}
+		if current == nil {
+			current = &token{}
+		}
+		if c == '"' {
+			isInQuotes = true
+			current.isQuoted = true
+			continue
+		}
+		current.value += string(c)
+	}
+	if isInQuotes {
+		return nil, errors.New("Unterminated quote")
+	}
+	if current != nil {
+		tokens = append(tokens, *current)
+	}
+	return tokens, nil
+}
@@ -0,0 +1,130 @@
+package timewarrior
+
+import (
+	"testing"
+	gotime "time"
+	_ "time/tzdata"
+
+	"github.com/jotaen/klog/klog"
+	"github.com/jotaen/klog/klog/parser"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var cet = gotime.FixedZone("CET", 60*60)
+
+func entryTexts(r klog.Record) []string {
+	var result []string
+	for _, e := range r.Entries() {
+		value := klog.Unbox(&e,
+			func(tr klog.Range) string { return tr.ToString() },
+			func(d klog.Duration) string { return d.ToString() },
+			func(o klog.OpenRange) string { return o.ToString() },
+		)
+		summary := parser.SummaryText(e.Summary()).ToString()
+		if summary != "" {
+			value += " " + summary
+		}
+		result = append(result, value)
+	}
+	return result
+}
+
+func TestSerialiseIntervals(t *testing.T) {
+	text := ToTimewarrior(func() []klog.Record {
+		r1 := klog.NewRecord(klog.Ɀ_Date_(2024, 1, 1))
+		r1.SetSummary(klog.Ɀ_RecordSummary_("#Project"))
+		r1.AddRange(klog.Ɀ_Range_(klog.Ɀ_TimeYesterday_(23, 0), klog.Ɀ_Time_(1, 30)), klog.Ɀ_EntrySummary_(`Said "hi" #client="ACME Corp"`))
+		r1.AddDuration(klog.NewDuration(1, 0), klog.Ɀ_EntrySummary_("Omitted"))
+		r2 := klog.NewRecord(klog.Ɀ_Date_(2024, 1, 2))
+		r2.AddRange(klog.Ɀ_Range_(klog.Ɀ_Time_(8, 0), klog.Ɀ_Time_(9, 0)), nil)
+		r2.Start(klog.NewOpenRange(klog.Ɀ_Time_(10, 0)), nil)
+		return []klog.Record{r1, r2}
+	}(), cet)
+	assert.Equal(t, `inc 20231231T220000Z - 20240101T003000Z # "client=ACME Corp" project # "Said \"hi\" #client=\"ACME Corp\""
+inc 20240102T070000Z - 20240102T080000Z
+inc 20240102T090000Z
+`, text)
+}
+
+func TestSerialiseIntervalsOnDstTransitionDays(t *testing.T) {
+	berlin, lErr := gotime.LoadLocation("Europe/Berlin")
+	require.Nil(t, lErr)
+	text := ToTimewarrior(func() []klog.Record {
+		// Daylight saving time starts at 2:00 on 2024-03-31.
+		r1 := klog.NewRecord(klog.Ɀ_Date_(2024, 3, 31))
+		r1.AddRange(klog.Ɀ_Range_(klog.Ɀ_TimeYesterday_(23, 0), klog.Ɀ_Time_(1, 0)), nil)
+		r1.AddRange(klog.Ɀ_Range_(klog.Ɀ_Time_(8, 0), klog.Ɀ_Time_(9, 0)), nil)
+		r2 := klog.NewRecord(klog.Ɀ_Date_(2024, 4, 1))
+		r2.AddRange(klog.Ɀ_Range_(klog.Ɀ_TimeYesterday_(23, 0), klog.Ɀ_Time_(1, 0)), nil)
+		// Daylight saving time ends at 3:00 on 2024-10-27.
+		r3 := klog.NewRecord(klog.Ɀ_Date_(2024, 10, 26))
+		r3.AddRange(klog.Ɀ_Range_(klog.Ɀ_Time_(22, 0), klog.Ɀ_TimeTomorrow_(8, 0)), nil)
+		return []klog.Record{r1, r2, r3}
+	}(), berlin)
+	assert.Equal(t, `inc 20240330T220000Z - 20240331T000000Z
+inc 20240331T060000Z - 20240331T070000Z
+inc 20240331T210000Z - 20240331T230000Z
+inc 20241026T200000Z - 20241027T070000Z
+`, text)
+}
+
+func TestDeserialiseIntervals(t *testing.T) {
+	rs, err := FromTimewarrior(`inc 20240101T080000Z - 20240101T093000Z # meeting "client x" # "Kick-off \"call\""
+inc 20240101T223000Z - 20240101T233000Z
+
+inc 20240102T063000Z - 20240102T070000Z # # "Without tags"
+inc 20240102T090000Z # coding
+`, cet)
+	require.Nil(t, err)
+	require.Len(t, rs, 2)
+	assert.Equal(t, "2024-01-01", rs[0].Date().ToString())
+	assert.Equal(t, []string{
+		`9:00 - 10:30 Kick-off "call" #meeting #client_x`,
+		"23:30 - 0:30>",
+	}, entryTexts(rs[0]))
+	assert.Equal(t, "2024-01-02", rs[1].Date().ToString())
+	assert.Equal(t, []string{
+		"7:30 - 8:00 Without tags",
+		"10:00 - ? #coding",
+	}, entryTexts(rs[1]))
+}
+
+func TestRoundTrip(t *testing.T) {
+	original := func() []klog.Record {
+		r1 := klog.NewRecord(klog.Ɀ_Date_(2024, 1, 1))
+		r1.AddRange(klog.Ɀ_Range_(klog.Ɀ_Time_(8, 0), klog.Ɀ_TimeTomorrow_(0, 30)), klog.Ɀ_EntrySummary_("Kick-off #meeting"))
+		r2 := klog.NewRecord(klog.Ɀ_Date_(2024, 1, 2))
+		r2.Start(klog.NewOpenRange(klog.Ɀ_Time_(9, 0)), klog.Ɀ_EntrySummary_("#coding"))
+		return []klog.Record{r1, r2}
+	}()
+	rs, err := FromTimewarrior(ToTimewarrior(original, cet), cet)
+	require.Nil(t, err)
+	require.Len(t, rs, 2)
+	for i := range original {
+		assert.Equal(t, original[i].Date().ToString(), rs[i].Date().ToString())
+		assert.Equal(t, entryTexts(original[i]), entryTexts(rs[i]))
+	}
+}
+
+func TestDeserialiseFailsForInvalidInput(t *testing.T) {
+	for _, x := range []struct {
+		text string
+		msg  string
+	}{
+		{"foo", "Line 1: Malformed interval"},
+		{"inc", "Line 1: Malformed interval"},
+		{"\ninc 2024-01-01", "Line 2: Invalid start time"},
+		{"inc 20240101T080000Z -", "Line 1: Missing end time"},
+		{"inc 20240101T080000Z - 123", "Line 1: Invalid end time"},
+		{"inc 20240101T080000Z foo", "Line 1: Malformed interval"},
+		{`inc 20240101T080000Z # "foo`, "Line 1: Unterminated quote"},
+		{"inc 20240101T080000Z - 20240101T070000Z", "Line 1: Start and end time must be in chronological order"},
+		{"inc 20240101T080000Z\ninc 20240101T090000Z", "Line 2: There can only be one open range per record"},
+	} {
+		rs, err := FromTimewarrior(x.text, gotime.UTC)
+		require.Error(t, err, x.text)
+		assert.Equal(t, x.msg, err.Error())
+		assert.Nil(t, rs)
+	}
+}
@@ -0,0 +1,107 @@
+package filter
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/jotaen/klog/klog"
+)
+
+// Explanation is the result of evaluating a predicate for a record or an
+// entry. For `&&`, `||` and negated groups, it contains the results of all
+// operands.
+type Explanation struct {
+	Predicate Predicate
+	IsMatch   bool
+	Operands  []Explanation
+}
+
+// Explain evaluates the predicate for the entry of the record, or for the
+// record itself if `e` is nil (which is how empty records are evaluated).
+// Other than the regular evaluation, it doesn’t short-circuit `&&` and `||`,
+// so that the results of all operands are available.
+func Explain(p Predicate, r klog.Record, e *klog.Entry) Explanation {
+	matches := func(p Predicate) bool {
+		if e == nil {
+			return p.MatchesEmptyRecord(r)
+		}
+		return p.Matches(r, *e)
+	}
+	x := Explanation{Predicate: p, IsMatch: matches(p)}
+	for _, op := range Operands(p) {
+		x.Operands = append(x.Operands, Explain(op, r, e))
+	}
+	return x
+}
+
+// Operands returns the operands of `&&`, `||` and negated groups. For all
+// other predicates, it returns nil.
+func Operands(p Predicate) []Predicate {
+	switch x := p.(type) {
+	case And:
+		return x.Predicates
+	case Or:
+		return x.Predicates
+	case Not:
+		if Operands(x.Predicate) != nil {
+			return []Predicate{x.Predicate}
+		}
+	}
+	return nil
+}
+
+// Describe returns a textual representation of the predicate in filter query
+// syntax. For predicates with operands (see Operands), it only returns the
+// operator.
+func Describe(p Predicate) string {
+	switch x := p.(type) {
+	case And:
+		return "&&"
+	case Or:
+		return "||"
+	case Not:
+		if Operands(x.Predicate) != nil {
+			return "!"
+		}
+		return "!" + Describe(x.Predicate)
+	case IsInDateRange:
+		if x.From != nil && x.To != nil && x.From.IsEqualTo(x.To) {
+			return x.From.ToString()
+		}
+		from, to := "", ""
+		if x.From != nil {
+			from = x.From.ToString()
+		}
+		if x.To != nil {
+			to = x.To.ToString()
+		}
+		return from + "..." + to
+	case IsOnWeekday:
+		if x.From == x.To {
+			return "weekday:" + weekdayNames[x.From-1][:3]
+		}
+		return "weekday:" + weekdayNames[x.From-1][:3] + "..." + weekdayNames[x.To-1][:3]
+	case HasTag:
+		return x.Tag.ToString()
+	case HasTagPattern:
+		return x.Pattern.ToString()
+	case SummaryContains:
+		return `summary~"` + x.Text + `"`
+	case SummaryMatches:
+		return "summary~/" + strings.ReplaceAll(x.Pattern.String(), "/", `\/`) + "/"
+	case HasDuration:
+		return "duration" + string(x.Comparator) + x.Duration.ToString()
+	case HasStartTime:
+		return "start" + string(x.Comparator) + x.Time.ToString()
+	case HasEndTime:
+		return "end" + string(x.Comparator) + x.Time.ToString()
+	case HasShouldTotal:
+		return "has:should"
+	case HasDiff:
+		return "diff" + string(x.Comparator) + x.Diff.ToString()
+	case IsEntryType:
+		return "type:" + string(x.Type)
+	}
+	// This should never happen, as all predicates are covered above.
+	return reflect.TypeOf(p).Name()
+}
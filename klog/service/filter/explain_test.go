@@ -0,0 +1,90 @@
+package filter
+
+import (
+	"testing"
+
+	"github.com/jotaen/klog/klog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExplainEvaluatesAllOperands(t *testing.T) {
+	rs := sampleRecordsForQuerying()
+	p, err := Parse("#bar || (#foo && !(#xyz || 2000-01-03))")
+	require.Nil(t, err)
+
+	// 2000-01-01, `6h #bar`:
+	x := Explain(p, rs[3], &rs[3].Entries()[1])
+	assert.True(t, x.IsMatch)
+	require.Len(t, x.Operands, 2)
+	assert.True(t, x.Operands[0].IsMatch)
+	and := x.Operands[1]
+	assert.True(t, and.IsMatch)
+	require.Len(t, and.Operands, 2)
+	assert.True(t, and.Operands[0].IsMatch)
+	not := and.Operands[1]
+	assert.True(t, not.IsMatch)
+	require.Len(t, not.Operands, 1)
+	or := not.Operands[0]
+	assert.False(t, or.IsMatch)
+	assert.False(t, or.Operands[0].IsMatch)
+	assert.False(t, or.Operands[1].IsMatch)
+	assert.Nil(t, or.Operands[0].Operands)
+
+	// 1999-12-30, which has no entries:
+	x = Explain(p, rs[1], nil)
+	assert.True(t, x.IsMatch)
+	assert.False(t, x.Operands[0].IsMatch)
+	assert.True(t, x.Operands[1].IsMatch)
+}
+
+func TestDescribePredicates(t *testing.T) {
+	for _, x := range []struct {
+		query    string
+		expected []string
+	}{
+		{"2020-01-01", []string{"2020-01-01"}},
+		{"2020-01-01...2020-01-31 || 2020-01... || ...2020-Q2", []string{"||", "2020-01-01...2020-01-31", "2020-01-01...", "...2020-06-30"}},
+		{"weekday:Monday || weekday:fri...mon", []string{"||", "weekday:mon", "weekday:fri...mon"}},
+		{"#foo || #bar=1 || #client-*", []string{"||", "#foo", "#bar=1", "#client-*"}},
+		{`summary~Foo || summary~/a\/b/`, []string{"||", `summary~"Foo"`, `summary~/a\/b/`}},
+		{"duration>=1h30m || start<8:00 || end=1:00>", []string{"||", "duration>=1h30m", "start<8:00", "end=1:00>"}},
+		{"type:open-range && !#foo", []string{"&&", "type:open-range", "!#foo"}},
+		{"!(#foo && #bar)", []string{"!", "&&", "#foo", "#bar"}},
+		{"has:should && diff<0", []string{"&&", "has:should", "diff<0m"}},
+	} {
+		t.Run(x.query, func(t *testing.T) {
+			p, err := Parse(x.query)
+			require.Nil(t, err)
+			var descriptions []string
+			var collect func(Predicate)
+			collect = func(p Predicate) {
+				descriptions = append(descriptions, Describe(p))
+				for _, o := range Operands(p) {
+					collect(o)
+				}
+			}
+			collect(p)
+			assert.Equal(t, x.expected, descriptions)
+		})
+	}
+}
+
+func TestExplainMatchesFilterResult(t *testing.T) {
+	p, err := Parse("(#foo || #bar) && !type:duration-negative")
+	require.Nil(t, err)
+	var expected []klog.Entry
+	for _, r := range sampleRecordsForQuerying() {
+		for i := range r.Entries() {
+			if Explain(p, r, &r.Entries()[i]).IsMatch {
+				expected = append(expected, r.Entries()[i])
+			}
+		}
+	}
+	var actual []klog.Entry
+	rs, _ := Filter(p, sampleRecordsForQuerying())
+	for _, r := range rs {
+		actual = append(actual, r.Entries()...)
+	}
+	assert.Equal(t, expected, actual)
+}
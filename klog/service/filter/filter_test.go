@@ -9,6 +9,9 @@ import (
 	"github.com/stretchr/testify/require"
 )
 
+// sampleToday is the reference date for resolving relative dates.
+var sampleToday = klog.Ɀ_Date_(2000, 1, 3)
+
 func sampleRecordsForQuerying() []klog.Record {
 	rs, _, err := parser.NewSerialParser().Parse(`
 1999-12-29
@@ -182,6 +185,36 @@ func TestQueryWithTagNonMatchingValues(t *testing.T) {
 	assertResult(t, []expect{}, rs)
 }
 
+func TestQueryWithHierarchicalTags(t *testing.T) {
+	rs, _, err := parser.NewSerialParser().Parse(`
+2000-01-01
+	1h #acme
+	2h #acme/backend
+	3h #acme/backend/api=v2
+	4h #acme/frontend
+	5h #acme-corp
+	6h #other/acme
+`)
+	require.Nil(t, err)
+	for _, x := range []struct {
+		tag    klog.Tag
+		expect []int
+	}{
+		{klog.NewTagOrPanic("acme", ""), []int{60, 120, 180, 240}},
+		{klog.NewTagOrPanic("acme/backend", ""), []int{120, 180}},
+		{klog.NewTagOrPanic("acme/backend/api", ""), []int{180}},
+		{klog.NewTagOrPanic("acme/backend/api", "v2"), []int{180}},
+		{klog.NewTagOrPanic("backend", ""), nil},
+	} {
+		result, _ := Filter(HasTag{x.tag}, rs)
+		if x.expect == nil {
+			assertResult(t, []expect{}, result)
+			continue
+		}
+		assertResult(t, []expect{{klog.Ɀ_Date_(2000, 1, 1), x.expect}}, result)
+	}
+}
+
 func TestQueryWithEntryTypes(t *testing.T) {
 	{
 		rs, hprws := Filter(IsEntryType{ENTRY_TYPE_DURATION}, sampleRecordsForQuerying())
@@ -227,6 +260,255 @@ func TestQueryWithEntryTypes(t *testing.T) {
 	}
 }
 
+func TestQueryWithSummaryText(t *testing.T) {
+	for _, x := range []struct {
+		query string
+		exp   []expect
+	}{
+		// Matches record summary (also for empty records), regardless of case.
+		{`summary~"hello world"`, []expect{{klog.Ɀ_Date_(1999, 12, 30), []int{}}}},
+		// Matches record summary (all entries) or entry summary (only matching entries).
+		{`summary~fifth || summary~xyz`, []expect{
+			{klog.Ɀ_Date_(2000, 1, 2), []int{420}},
+			{klog.Ɀ_Date_(2000, 1, 3), []int{240, 180, 0}},
+		}},
+		// Treats line breaks as spaces.
+		{`summary~"[240] #bar=1"`, []expect{{klog.Ɀ_Date_(2000, 1, 3), []int{240}}}},
+		{`summary~/^#bar\s+\[\d+\]$/ && !2000-01-01`, []expect{{klog.Ɀ_Date_(1999, 12, 31), []int{300}}}},
+		{`summary~/^#BAR/`, nil},
+	} {
+		t.Run(x.query, func(t *testing.T) {
+			p, err := Parse(x.query)
+			require.Nil(t, err)
+			rs, _ := Filter(p, sampleRecordsForQuerying())
+			assertResult(t, x.exp, rs)
+		})
+	}
+}
+
+func TestQueryWithTagPatterns(t *testing.T) {
+	for _, x := range []struct {
+		query string
+		exp   []expect
+	}{
+		// Matches record tags (also for empty records) and entry tags.
+		{`#fi*`, []expect{
+			{klog.Ɀ_Date_(1999, 12, 30), []int{}},
+			{klog.Ɀ_Date_(2000, 1, 3), []int{240, 180, 0}},
+		}},
+		{`#*r && !#foo`, []expect{{klog.Ɀ_Date_(1999, 12, 31), []int{300}}}},
+		{`#bar=*`, []expect{{klog.Ɀ_Date_(2000, 1, 3), []int{240, 180}}}},
+		{`#b*=2`, []expect{{klog.Ɀ_Date_(2000, 1, 3), []int{180}}}},
+		{`#x*z || #*xyz*`, []expect{{klog.Ɀ_Date_(2000, 1, 2), []int{420}}}},
+		{`#foo-*`, nil},
+	} {
+		t.Run(x.query, func(t *testing.T) {
+			p, err := Parse(x.query)
+			require.Nil(t, err)
+			rs, _ := Filter(p, sampleRecordsForQuerying())
+			assertResult(t, x.exp, rs)
+		})
+	}
+}
+
+func TestQueryWithRelativeDates(t *testing.T) {
+	for _, x := range []struct {
+		query string
+		exp   []expect
+	}{
+		{`today`, []expect{{klog.Ɀ_Date_(2000, 1, 3), []int{240, 180, 0}}}},
+		{`yesterday...today && #xyz`, []expect{{klog.Ɀ_Date_(2000, 1, 2), []int{420}}}},
+		{`-3d...-2d`, []expect{
+			{klog.Ɀ_Date_(1999, 12, 31), []int{300}},
+			{klog.Ɀ_Date_(2000, 1, 1), []int{15, 360, -30}},
+		}},
+		{`...-1w || -4d`, []expect{{klog.Ɀ_Date_(1999, 12, 30), []int{}}}},
+	} {
+		t.Run(x.query, func(t *testing.T) {
+			p, err := ParseRelativeTo(x.query, sampleToday)
+			require.Nil(t, err)
+			rs, _ := Filter(p, sampleRecordsForQuerying())
+			assertResult(t, x.exp, rs)
+		})
+	}
+}
+
+func TestQueryWithWeekday(t *testing.T) {
+	for _, x := range []struct {
+		query string
+		exp   []expect
+	}{
+		// Matches records on that weekday (also empty records).
+		{`weekday:wed`, []expect{{klog.Ɀ_Date_(1999, 12, 29), []int{}}}},
+		{`weekday:sat || weekday:SUNDAY`, []expect{
+			{klog.Ɀ_Date_(2000, 1, 1), []int{15, 360, -30}},
+			{klog.Ɀ_Date_(2000, 1, 2), []int{420}},
+		}},
+		{`weekday:thu...fri`, []expect{
+			{klog.Ɀ_Date_(1999, 12, 30), []int{}},
+			{klog.Ɀ_Date_(1999, 12, 31), []int{300}},
+		}},
+		// Wraps around the end of the week.
+		{`weekday:sun...mon`, []expect{
+			{klog.Ɀ_Date_(2000, 1, 2), []int{420}},
+			{klog.Ɀ_Date_(2000, 1, 3), []int{240, 180, 0}},
+		}},
+		{`weekday:mon...fri && #bar`, []expect{
+			{klog.Ɀ_Date_(1999, 12, 31), []int{300}},
+			{klog.Ɀ_Date_(2000, 1, 3), []int{240, 180}},
+		}},
+	} {
+		t.Run(x.query, func(t *testing.T) {
+			p, err := Parse(x.query)
+			require.Nil(t, err)
+			rs, _ := Filter(p, sampleRecordsForQuerying())
+			assertResult(t, x.exp, rs)
+		})
+	}
+}
+
+func TestQueryWithDuration(t *testing.T) {
+	for _, x := range []struct {
+		query string
+		exp   []expect
+	}{
+		{`duration>5h`, []expect{
+			{klog.Ɀ_Date_(2000, 1, 1), []int{360}},
+			{klog.Ɀ_Date_(2000, 1, 2), []int{420}},
+		}},
+		{`duration>=5h`, []expect{
+			{klog.Ɀ_Date_(1999, 12, 31), []int{300}},
+			{klog.Ɀ_Date_(2000, 1, 1), []int{360}},
+			{klog.Ɀ_Date_(2000, 1, 2), []int{420}},
+		}},
+		// Open ranges never match, as their duration is unknown.
+		{`duration<1h`, []expect{{klog.Ɀ_Date_(2000, 1, 1), []int{15, -30}}}},
+		{`duration<=15m && type:range`, []expect{{klog.Ɀ_Date_(2000, 1, 1), []int{15}}}},
+		{`duration=4h || duration=-30m`, []expect{
+			{klog.Ɀ_Date_(2000, 1, 1), []int{-30}},
+			{klog.Ɀ_Date_(2000, 1, 3), []int{240}},
+		}},
+		{`duration>3h && !#bar`, []expect{{klog.Ɀ_Date_(2000, 1, 2), []int{420}}}},
+	} {
+		t.Run(x.query, func(t *testing.T) {
+			p, err := Parse(x.query)
+			require.Nil(t, err)
+			rs, _ := Filter(p, sampleRecordsForQuerying())
+			assertResult(t, x.exp, rs)
+		})
+	}
+}
+
+func TestQueryWithStartAndEndTime(t *testing.T) {
+	text := `
+2000-01-01
+	<22:00 - 0:30
+	6:00 - 8:00
+	9:00 - 17:00
+	2h
+
+2000-01-02
+	17:00 - 19:00
+	23:00 - 1:00>
+	20:00 - ?
+`
+	for _, x := range []struct {
+		query string
+		exp   []expect
+	}{
+		// Shifted times are before / after all unshifted times.
+		{`start>=18:00`, []expect{{klog.Ɀ_Date_(2000, 1, 2), []int{120, 0}}}},
+		{`start<0:00`, []expect{{klog.Ɀ_Date_(2000, 1, 1), []int{150}}}},
+		{`end>23:59`, []expect{{klog.Ɀ_Date_(2000, 1, 2), []int{120}}}},
+		{`end<=<23:00`, nil},
+		{`end=8:00`, []expect{{klog.Ɀ_Date_(2000, 1, 1), []int{120}}}},
+		// Open ranges only have a start time; durations have neither.
+		{`start>=18:00 || end<=7:00`, []expect{
+			{klog.Ɀ_Date_(2000, 1, 1), []int{150}},
+			{klog.Ɀ_Date_(2000, 1, 2), []int{120, 0}},
+		}},
+		{`start<7:00 || end>18:00`, []expect{
+			{klog.Ɀ_Date_(2000, 1, 1), []int{150, 120}},
+			{klog.Ɀ_Date_(2000, 1, 2), []int{120, 120}},
+		}},
+		{`(start>=17:00 && !end>1:00>) && type:range`, []expect{{klog.Ɀ_Date_(2000, 1, 2), []int{120, 120}}}},
+		{`!start>=6:00 && !start<6:00`, []expect{{klog.Ɀ_Date_(2000, 1, 1), []int{120}}}},
+	} {
+		t.Run(x.query, func(t *testing.T) {
+			rs, _, err := parser.NewSerialParser().Parse(text)
+			require.Nil(t, err)
+			p, pErr := Parse(x.query)
+			require.Nil(t, pErr)
+			result, _ := Filter(p, rs)
+			assertResult(t, x.exp, result)
+		})
+	}
+}
+
+func TestQueryWithShouldTotalAndDiff(t *testing.T) {
+	text := `
+2000-01-01 (8h!)
+	8h
+
+2000-01-02 (8h!)
+	5h #foo
+	1h
+
+2000-01-03 (6h!)
+	7h #foo
+
+2000-01-04
+	3h
+
+2000-01-05 (4h!)
+`
+	for _, x := range []struct {
+		query string
+		exp   []expect
+	}{
+		{`has:should`, []expect{
+			{klog.Ɀ_Date_(2000, 1, 1), []int{480}},
+			{klog.Ɀ_Date_(2000, 1, 2), []int{300, 60}},
+			{klog.Ɀ_Date_(2000, 1, 3), []int{420}},
+			{klog.Ɀ_Date_(2000, 1, 5), []int{}},
+		}},
+		{`!has:should`, []expect{{klog.Ɀ_Date_(2000, 1, 4), []int{180}}}},
+		{`diff<0`, []expect{
+			{klog.Ɀ_Date_(2000, 1, 2), []int{300, 60}},
+			{klog.Ɀ_Date_(2000, 1, 5), []int{}},
+		}},
+		{`diff>=0m`, []expect{
+			{klog.Ɀ_Date_(2000, 1, 1), []int{480}},
+			{klog.Ɀ_Date_(2000, 1, 3), []int{420}},
+		}},
+		{`diff=0 || diff>+30m`, []expect{
+			{klog.Ɀ_Date_(2000, 1, 1), []int{480}},
+			{klog.Ɀ_Date_(2000, 1, 3), []int{420}},
+		}},
+		{`diff<=-2h`, []expect{
+			{klog.Ɀ_Date_(2000, 1, 2), []int{300, 60}},
+			{klog.Ɀ_Date_(2000, 1, 5), []int{}},
+		}},
+		// The diff is evaluated for the entire record, regardless of entry-level filtering.
+		{`diff<0 && #foo`, []expect{{klog.Ɀ_Date_(2000, 1, 2), []int{300}}}},
+		// Records without should-total never match, but their negation does.
+		{`!(diff<0)`, []expect{
+			{klog.Ɀ_Date_(2000, 1, 1), []int{480}},
+			{klog.Ɀ_Date_(2000, 1, 3), []int{420}},
+			{klog.Ɀ_Date_(2000, 1, 4), []int{180}},
+		}},
+	} {
+		t.Run(x.query, func(t *testing.T) {
+			rs, _, err := parser.NewSerialParser().Parse(text)
+			require.Nil(t, err)
+			p, pErr := Parse(x.query)
+			require.Nil(t, pErr)
+			result, _ := Filter(p, rs)
+			assertResult(t, x.exp, result)
+		})
+	}
+}
+
 func TestComplexFilterQueries(t *testing.T) {
 	{
 		rs, hprws := Filter(Or{[]Predicate{
@@ -0,0 +1,63 @@
+package filter
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// NamedFilters maps names to filter queries. They can be referenced in other
+// filter queries by their name, e.g. `@billable`.
+type NamedFilters map[string]string
+
+var namedFilterNamePattern = regexp.MustCompile(`^[\p{L}\d_-]+$`)
+
+// NewNamedFiltersFromString parses a list of named filters, which are separated
+// by semicolons. Every named filter consists of a name and a filter query, which
+// are separated by a colon, e.g.: `billable: #client-* && !#internal; weekend:
+// weekday:sat...sun`. Blank items are ignored.
+// The syntax of the filter queries is validated, whereby they cannot reference
+// other named filters themselves.
+func NewNamedFiltersFromString(text string) (NamedFilters, error) {
+	namedFilters := make(NamedFilters)
+	for _, item := range strings.Split(text, ";") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		name, query, hasColon := strings.Cut(item, ":")
+		name = strings.TrimSpace(name)
+		query = strings.TrimSpace(query)
+		if !hasColon || !namedFilterNamePattern.MatchString(name) {
+			return nil, errors.New("Invalid named filter `" + item + "`: it must start with a name, followed by a colon, e.g. `billable: #client`")
+		}
+		if _, exists := namedFilters[name]; exists {
+			return nil, errors.New("Duplicate named filter `" + name + "`")
+		}
+		if query == "" {
+			return nil, errors.New("Invalid named filter `" + name + "`: the filter query is empty")
+		}
+		pErr := Validate(query)
+		if pErr != nil {
+			pos, _ := pErr.Position()
+			return nil, fmt.Errorf(
+				"Invalid named filter `%s`: the filter query `%s` is malformed at position %d. %s",
+				name, query, pos, pErr.Original().Error(),
+			)
+		}
+		namedFilters[name] = query
+	}
+	return namedFilters, nil
+}
+
+// Names returns the names of all named filters in alphabetical order.
+func (n NamedFilters) Names() []string {
+	names := make([]string, 0, len(n))
+	for name := range n {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,56 @@
+package filter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsesNamedFilters(t *testing.T) {
+	for _, txt := range []string{
+		"",
+		"billable: #client-* && !#internal; weekend: weekday:sat...sun",
+		"  billable:#client-* && !#internal ;weekend :weekday:sat...sun;  ",
+	} {
+		t.Run(txt, func(t *testing.T) {
+			nfs, err := NewNamedFiltersFromString(txt)
+			require.Nil(t, err)
+			if txt == "" {
+				assert.Empty(t, nfs)
+				return
+			}
+			assert.Equal(t, NamedFilters{
+				"billable": "#client-* && !#internal",
+				"weekend":  "weekday:sat...sun",
+			}, nfs)
+			assert.Equal(t, []string{"billable", "weekend"}, nfs.Names())
+		})
+	}
+}
+
+func TestRejectsInvalidNamedFilters(t *testing.T) {
+	for _, txt := range []string{
+		"#client-* && !#internal",
+		": #client-*",
+		"bill able: #client-*",
+		"@billable: #client-*",
+		"billable:",
+		"billable: #client-*; billable: #acme",
+		"billable: #client-* &&",
+		"billable: #client-*; weekend: weekday:sat...sun && @billable",
+	} {
+		t.Run(txt, func(t *testing.T) {
+			nfs, err := NewNamedFiltersFromString(txt)
+			require.Error(t, err)
+			assert.Nil(t, nfs)
+		})
+	}
+}
+
+func TestErrorOfInvalidNamedFilterQueryContainsDetails(t *testing.T) {
+	_, err := NewNamedFiltersFromString("billable: #client-* && foo")
+	require.Error(t, err)
+	assert.Equal(t, "Invalid named filter `billable`: the filter query `#client-* && foo` is malformed at position 13. "+
+		"Unrecognised query token. Please make sure to use valid query syntax.", err.Error())
+}
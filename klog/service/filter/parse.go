@@ -3,7 +3,9 @@ package filter
 import (
 	"errors"
 	"fmt"
+	"regexp"
 	"strings"
+	gotime "time"
 
 	"github.com/jotaen/klog/klog"
 	"github.com/jotaen/klog/klog/service/period"
@@ -19,9 +21,32 @@ var (
 	ErrOperatorExpected       = fmt.Errorf("%w operator. Please put a logical operator ('&&' or '||') before this search operand.", errOperatorOperand)
 	ErrOperandExpected        = fmt.Errorf("%w filter term. Please remove redundant logical operators.", errOperatorOperand)
 	ErrIllegalTokenValue      = errors.New("Illegal value. Please make sure to use only valid operand values.")
+	ErrUnknownNamedFilter     = errors.New("Unknown named filter. Please make sure that it’s defined in the `named_filters` setting of the config file.")
 )
 
+// Parse parses a filter query into a predicate. Relative dates in the query
+// (e.g. `today` or `-14d`) are resolved in relation to the current date.
 func Parse(filterQuery string) (Predicate, ParseError) {
+	return ParseRelativeTo(filterQuery, klog.NewDateFromGo(gotime.Now()))
+}
+
+// ParseRelativeTo is like Parse, but it resolves relative dates in relation
+// to `today`.
+func ParseRelativeTo(filterQuery string, today klog.Date) (Predicate, ParseError) {
+	return ParseWithNamedFilters(filterQuery, today, nil)
+}
+
+// Validate checks whether a filter query is well-formed. The reference date
+// for resolving relative dates is arbitrary, since it doesn’t matter for
+// the syntax of the query.
+func Validate(filterQuery string) ParseError {
+	_, err := ParseRelativeTo(filterQuery, klog.NewDateFromGo(gotime.Date(2000, 1, 1, 0, 0, 0, 0, gotime.UTC)))
+	return err
+}
+
+// ParseWithNamedFilters is like ParseRelativeTo, but it additionally resolves
+// references to named filters (e.g. `@billable`) from `namedFilters`.
+func ParseWithNamedFilters(filterQuery string, today klog.Date, namedFilters NamedFilters) (Predicate, ParseError) {
 	p, pErr := func() (Predicate, ParseError) {
 		tokens, pErr := tokenise(filterQuery)
 		if pErr != nil {
@@ -30,7 +55,7 @@ func Parse(filterQuery string) (Predicate, ParseError) {
 		tp := newTokenParser(
 			append(tokens, token{tokenCloseBracket, ")", len(filterQuery) - 1}),
 		)
-		p, pErr := parseGroup(&tp, filterQuery)
+		p, pErr := parseGroup(&tp, filterQuery, today, namedFilters)
 		if pErr != nil {
 			return nil, pErr
 		}
@@ -54,7 +79,32 @@ func Parse(filterQuery string) (Predicate, ParseError) {
 	return p, nil
 }
 
-func parseGroup(tp *tokenParser, filterQuery string) (Predicate, ParseError) {
+// splitComparison splits up the value of a comparison token into the
+// comparator and the operand value. It also returns the position of the
+// operand value in the filter query.
+func splitComparison(tk token, name string) (Comparator, string, int) {
+	value := strings.TrimPrefix(tk.value, name)
+	for _, c := range []Comparator{
+		COMPARE_LESS_OR_EQUAL, COMPARE_GREATER_OR_EQUAL, COMPARE_LESS, COMPARE_GREATER, COMPARE_EQUAL,
+	} {
+		if strings.HasPrefix(value, string(c)) {
+			return c, value[len(c):], tk.position + len(name) + len(c)
+		}
+	}
+	// This should never happen, as the tokeniser only yields valid comparators.
+	panic("Unrecognized comparator")
+}
+
+// parseDurationOperand parses the duration value of a comparison. Besides the
+// regular duration notation, it accepts `0` as shorthand for `0m`.
+func parseDurationOperand(value string) (klog.Duration, error) {
+	if strings.TrimLeft(value, "+-") == "0" {
+		return klog.NewDuration(0, 0), nil
+	}
+	return klog.NewDurationFromString(value)
+}
+
+func parseGroup(tp *tokenParser, filterQuery string, today klog.Date, namedFilters NamedFilters) (Predicate, ParseError) {
 	g := newPredicateGroup()
 
 	if pErr := tp.checkNextIsOperand(); pErr != nil {
@@ -77,7 +127,7 @@ func parseGroup(tp *tokenParser, filterQuery string) (Predicate, ParseError) {
 			if pErr := tp.checkNextIsOperand(); pErr != nil {
 				return nil, pErr
 			}
-			p, pErr := parseGroup(tp, filterQuery)
+			p, pErr := parseGroup(tp, filterQuery, today, namedFilters)
 			if pErr != nil {
 				return nil, pErr
 			}
@@ -94,7 +144,7 @@ func parseGroup(tp *tokenParser, filterQuery string) (Predicate, ParseError) {
 			if pErr := tp.checkNextIsOperatorOrEnd(); pErr != nil {
 				return nil, pErr
 			}
-			date, err := klog.NewDateFromString(tk.value)
+			date, err := period.NewDateFromAbsoluteOrRelativeString(tk.value, today)
 			if err != nil {
 				return nil, parseError{
 					err:      ErrIllegalTokenValue,
@@ -124,8 +174,8 @@ func parseGroup(tp *tokenParser, filterQuery string) (Predicate, ParseError) {
 					}
 					continue
 				}
-				// Try whether bound is date:
-				date, err := klog.NewDateFromString(v)
+				// Try whether bound is date (absolute or relative):
+				date, err := period.NewDateFromAbsoluteOrRelativeString(v, today)
 				if err == nil {
 					dateBoundaries[i] = date
 					continue
@@ -191,19 +241,169 @@ func parseGroup(tp *tokenParser, filterQuery string) (Predicate, ParseError) {
 			}
 			g.append(IsEntryType{et})
 
+		case tokenWeekday:
+			if pErr := tp.checkNextIsOperatorOrEnd(); pErr != nil {
+				return nil, pErr
+			}
+			bounds := strings.Split(strings.TrimPrefix(tk.value, "weekday:"), "...")
+			weekdays := make([]int, len(bounds))
+			for i, v := range bounds {
+				wd, err := NewWeekdayFromString(v)
+				if err != nil || len(bounds) > 2 {
+					return nil, parseError{
+						err:      ErrIllegalTokenValue,
+						position: tk.position,
+						length:   len(tk.value),
+					}
+				}
+				weekdays[i] = wd
+			}
+			g.append(IsOnWeekday{weekdays[0], weekdays[len(weekdays)-1]})
+
 		case tokenTag:
 			if pErr := tp.checkNextIsOperatorOrEnd(); pErr != nil {
 				return nil, pErr
 			}
-			tag, err := klog.NewTagFromString(tk.value)
+			if strings.Contains(tk.value, "*") {
+				pattern, err := klog.NewTagPatternFromString(tk.value)
+				if err != nil {
+					return nil, parseError{
+						err:      ErrIllegalTokenValue,
+						position: tk.position,
+						length:   len(tk.value),
+					}
+				}
+				g.append(HasTagPattern{pattern})
+			} else {
+				tag, err := klog.NewTagFromString(tk.value)
+				if err != nil {
+					return nil, parseError{
+						err:      ErrIllegalTokenValue,
+						position: tk.position,
+						length:   len(tk.value),
+					}
+				}
+				g.append(HasTag{tag})
+			}
+
+		case tokenDuration:
+			if pErr := tp.checkNextIsOperatorOrEnd(); pErr != nil {
+				return nil, pErr
+			}
+			comparator, value, valuePosition := splitComparison(tk, "duration")
+			duration, err := parseDurationOperand(value)
+			if err != nil {
+				return nil, parseError{
+					err:      ErrIllegalTokenValue,
+					position: valuePosition,
+					length:   max(len(value), 1),
+				}
+			}
+			g.append(HasDuration{comparator, duration})
+
+		case tokenDiff:
+			if pErr := tp.checkNextIsOperatorOrEnd(); pErr != nil {
+				return nil, pErr
+			}
+			comparator, value, valuePosition := splitComparison(tk, "diff")
+			diff, err := parseDurationOperand(value)
+			if err != nil {
+				return nil, parseError{
+					err:      ErrIllegalTokenValue,
+					position: valuePosition,
+					length:   max(len(value), 1),
+				}
+			}
+			g.append(HasDiff{comparator, diff})
+
+		case tokenHas:
+			if pErr := tp.checkNextIsOperatorOrEnd(); pErr != nil {
+				return nil, pErr
+			}
+			if strings.TrimPrefix(tk.value, "has:") != "should" {
+				return nil, parseError{
+					err:      ErrIllegalTokenValue,
+					position: tk.position,
+					length:   len(tk.value),
+				}
+			}
+			g.append(HasShouldTotal{})
+
+		case tokenStartTime, tokenEndTime:
+			if pErr := tp.checkNextIsOperatorOrEnd(); pErr != nil {
+				return nil, pErr
+			}
+			name := "start"
+			if tk.kind == tokenEndTime {
+				name = "end"
+			}
+			comparator, value, valuePosition := splitComparison(tk, name)
+			time, err := klog.NewTimeFromString(value)
+			if err != nil {
+				return nil, parseError{
+					err:      ErrIllegalTokenValue,
+					position: valuePosition,
+					length:   max(len(value), 1),
+				}
+			}
+			if tk.kind == tokenEndTime {
+				g.append(HasEndTime{comparator, time})
+			} else {
+				g.append(HasStartTime{comparator, time})
+			}
+
+		case tokenNamedFilter:
+			if pErr := tp.checkNextIsOperatorOrEnd(); pErr != nil {
+				return nil, pErr
+			}
+			query, ok := namedFilters[strings.TrimPrefix(tk.value, "@")]
+			if !ok {
+				return nil, parseError{
+					err:      ErrUnknownNamedFilter,
+					position: tk.position,
+					length:   len(tk.value),
+				}
+			}
+			// Named filters cannot reference other named filters, so that
+			// there is no risk of circular references.
+			p, err := ParseRelativeTo(query, today)
 			if err != nil {
+				return nil, parseError{
+					err:      fmt.Errorf("Invalid named filter `%s`: %w", tk.value, err.Original()),
+					position: tk.position,
+					length:   len(tk.value),
+				}
+			}
+			g.append(p)
+
+		case tokenSummary:
+			if pErr := tp.checkNextIsOperatorOrEnd(); pErr != nil {
+				return nil, pErr
+			}
+			value := strings.TrimPrefix(tk.value, "summary~")
+			if len(value) >= 2 && strings.HasPrefix(value, "/") && strings.HasSuffix(value, "/") {
+				pattern, err := regexp.Compile(strings.ReplaceAll(value[1:len(value)-1], `\/`, "/"))
+				if err != nil {
+					return nil, parseError{
+						err:      ErrIllegalTokenValue,
+						position: tk.position,
+						length:   len(tk.value),
+					}
+				}
+				g.append(SummaryMatches{pattern})
+				continue
+			}
+			if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') {
+				value = value[1 : len(value)-1]
+			}
+			if value == "" {
 				return nil, parseError{
 					err:      ErrIllegalTokenValue,
 					position: tk.position,
 					length:   len(tk.value),
 				}
 			}
-			g.append(HasTag{tag})
+			g.append(SummaryContains{value})
 
 		default:
 			// This should never happen.
@@ -1,6 +1,7 @@
 package filter
 
 import (
+	"regexp"
 	"testing"
 
 	"github.com/jotaen/klog/klog"
@@ -170,6 +171,30 @@ func TestOpenDateRangeUntil(t *testing.T) {
 	}
 }
 
+func TestRelativeDates(t *testing.T) {
+	for _, tt := range []struct {
+		input string
+		from  klog.Date
+		to    klog.Date
+	}{
+		{"today", klog.Ɀ_Date_(2000, 1, 3), klog.Ɀ_Date_(2000, 1, 3)},
+		{"yesterday", klog.Ɀ_Date_(2000, 1, 2), klog.Ɀ_Date_(2000, 1, 2)},
+		{"-2w", klog.Ɀ_Date_(1999, 12, 20), klog.Ɀ_Date_(1999, 12, 20)},
+		{"-14d...", klog.Ɀ_Date_(1999, 12, 20), nil},
+		{"...-1d", nil, klog.Ɀ_Date_(2000, 1, 2)},
+		{"yesterday...today", klog.Ɀ_Date_(2000, 1, 2), klog.Ɀ_Date_(2000, 1, 3)},
+		{"-1w...+3d", klog.Ɀ_Date_(1999, 12, 27), klog.Ɀ_Date_(2000, 1, 6)},
+		{"1999-12...today", klog.Ɀ_Date_(1999, 12, 1), klog.Ɀ_Date_(2000, 1, 3)},
+		{"-5d...2000-01-31", klog.Ɀ_Date_(1999, 12, 29), klog.Ɀ_Date_(2000, 1, 31)},
+	} {
+		t.Run(tt.input, func(t *testing.T) {
+			p, err := ParseRelativeTo(tt.input, sampleToday)
+			require.Nil(t, err)
+			assert.Equal(t, IsInDateRange{tt.from, tt.to}, p)
+		})
+	}
+}
+
 func TestPeriod(t *testing.T) {
 	p, err := Parse("2020 || 2021-Q2 || 2022-08 || 2023-W46")
 	require.Nil(t, err)
@@ -193,6 +218,18 @@ func TestTags(t *testing.T) {
 		}}, p)
 }
 
+func TestTagPatterns(t *testing.T) {
+	p, err := Parse("#client-* || #ticket=PROJ-* || #*=\"a *\" || #foo")
+	require.Nil(t, err)
+	assert.Equal(t,
+		Or{[]Predicate{
+			HasTagPattern{klog.NewTagPatternOrPanic("client-*", "")},
+			HasTagPattern{klog.NewTagPatternOrPanic("ticket", "PROJ-*")},
+			HasTagPattern{klog.NewTagPatternOrPanic("*", "a *")},
+			HasTag{klog.NewTagOrPanic("foo", "")},
+		}}, p)
+}
+
 func TestEntryType(t *testing.T) {
 	p, err := Parse("type:duration || type:range || type:open-range || type:duration-positive || type:duration-negative")
 	require.Nil(t, err)
@@ -206,6 +243,128 @@ func TestEntryType(t *testing.T) {
 		}}, p)
 }
 
+func TestSummary(t *testing.T) {
+	p, err := Parse(`summary~review || summary~"code review" || summary~'say "hi"' || summary~/^Fix (bug|issue) \/ \d+$/`)
+	require.Nil(t, err)
+	assert.Equal(t,
+		Or{[]Predicate{
+			SummaryContains{"review"},
+			SummaryContains{"code review"},
+			SummaryContains{`say "hi"`},
+			SummaryMatches{regexp.MustCompile(`^Fix (bug|issue) / \d+$`)},
+		}}, p)
+}
+
+func TestWeekday(t *testing.T) {
+	p, err := Parse("weekday:mon || weekday:Sunday || weekday:tue...fri || weekday:sat...mon")
+	require.Nil(t, err)
+	assert.Equal(t,
+		Or{[]Predicate{
+			IsOnWeekday{1, 1},
+			IsOnWeekday{7, 7},
+			IsOnWeekday{2, 5},
+			IsOnWeekday{6, 1},
+		}}, p)
+}
+
+func TestDuration(t *testing.T) {
+	p, err := Parse("duration<30m || duration<=1h || duration>2h15m || duration>=-45m || duration=8h")
+	require.Nil(t, err)
+	assert.Equal(t,
+		Or{[]Predicate{
+			HasDuration{COMPARE_LESS, klog.NewDuration(0, 30)},
+			HasDuration{COMPARE_LESS_OR_EQUAL, klog.NewDuration(1, 0)},
+			HasDuration{COMPARE_GREATER, klog.NewDuration(2, 15)},
+			HasDuration{COMPARE_GREATER_OR_EQUAL, klog.NewDuration(0, -45)},
+			HasDuration{COMPARE_EQUAL, klog.NewDuration(8, 0)},
+		}}, p)
+}
+
+func TestStartAndEndTime(t *testing.T) {
+	p, err := Parse("start>=18:00 || start<<23:00 || end<7:00 || end=1:00> || start<=8:30am || end>6:00pm")
+	require.Nil(t, err)
+	assert.Equal(t,
+		Or{[]Predicate{
+			HasStartTime{COMPARE_GREATER_OR_EQUAL, klog.Ɀ_Time_(18, 0)},
+			HasStartTime{COMPARE_LESS, klog.Ɀ_TimeYesterday_(23, 0)},
+			HasEndTime{COMPARE_LESS, klog.Ɀ_Time_(7, 0)},
+			HasEndTime{COMPARE_EQUAL, klog.Ɀ_TimeTomorrow_(1, 0)},
+			HasStartTime{COMPARE_LESS_OR_EQUAL, klog.Ɀ_IsAmPm_(klog.Ɀ_Time_(8, 30))},
+			HasEndTime{COMPARE_GREATER, klog.Ɀ_IsAmPm_(klog.Ɀ_Time_(18, 0))},
+		}}, p)
+}
+
+func TestNamedFilters(t *testing.T) {
+	namedFilters := NamedFilters{
+		"billable": "#client-* && !#internal",
+		"weekend":  "weekday:sat...sun",
+	}
+	p, err := ParseWithNamedFilters("@billable && !@weekend && 2020-Q1", sampleToday, namedFilters)
+	require.Nil(t, err)
+	assert.Equal(t,
+		And{[]Predicate{
+			And{[]Predicate{
+				HasTagPattern{klog.NewTagPatternOrPanic("client-*", "")},
+				Not{HasTag{klog.NewTagOrPanic("internal", "")}},
+			}},
+			Not{IsOnWeekday{6, 7}},
+			IsInDateRange{klog.Ɀ_Date_(2020, 1, 1), klog.Ɀ_Date_(2020, 3, 31)},
+		}}, p)
+}
+
+func TestNamedFilterErrorsMentionTheName(t *testing.T) {
+	namedFilters := NamedFilters{
+		"broken": "#foo &&",
+	}
+	p, err := ParseWithNamedFilters("2020-Q1 && @broken", sampleToday, namedFilters)
+	require.Error(t, err)
+	require.Nil(t, p)
+	assert.ErrorIs(t, err.Original(), ErrOperandExpected)
+	assert.Contains(t, err.Original().Error(), "Invalid named filter `@broken`")
+	position, length := err.Position()
+	assert.Equal(t, 11, position)
+	assert.Equal(t, 7, length)
+}
+
+func TestValidateChecksSyntaxOnly(t *testing.T) {
+	for _, query := range []string{"today", "-14d...yesterday", "#foo && -2w..."} {
+		assert.Nil(t, Validate(query), query)
+	}
+	for _, query := range []string{"#foo &&", "-14x", "@billable"} {
+		assert.Error(t, Validate(query), query)
+	}
+}
+
+func TestZeroDurationOperands(t *testing.T) {
+	for _, query := range []string{
+		"duration=0", "duration=0m", "duration=+0", "duration=-0",
+		"diff=0", "diff=0m", "diff=+0", "diff=-0",
+	} {
+		t.Run(query, func(t *testing.T) {
+			p, err := Parse(query)
+			require.Nil(t, err)
+			assert.Contains(t, []Predicate{
+				HasDuration{COMPARE_EQUAL, klog.NewDuration(0, 0)},
+				HasDiff{COMPARE_EQUAL, klog.NewDuration(0, 0)},
+			}, p)
+		})
+	}
+}
+
+func TestShouldTotalAndDiff(t *testing.T) {
+	p, err := Parse("has:should && (diff<0 || diff>=1h30m || diff=-15m)")
+	require.Nil(t, err)
+	assert.Equal(t,
+		And{[]Predicate{
+			HasShouldTotal{},
+			Or{[]Predicate{
+				HasDiff{COMPARE_LESS, klog.NewDuration(0, 0)},
+				HasDiff{COMPARE_GREATER_OR_EQUAL, klog.NewDuration(1, 30)},
+				HasDiff{COMPARE_EQUAL, klog.NewDuration(0, -15)},
+			}},
+		}}, p)
+}
+
 func TestBracketMismatch(t *testing.T) {
 	for _, tt := range []et{
 		{"(2020-01", errUnbalancedBrackets, 0, 8},
@@ -298,7 +457,27 @@ func TestMalformedOperandError(t *testing.T) {
 		{"2020-01-02...2020-01-01", ErrIllegalTokenValue, 0, 23},
 		{"2020-Q7", ErrIllegalTokenValue, 0, 7},
 		{"type:foo", ErrIllegalTokenValue, 0, 8},
+		{"#foo || today...-2w", ErrIllegalTokenValue, 8, 11},
+		{"-99999999w", ErrIllegalTokenValue, 0, 10},
+		{"#foo && summary~/(/", ErrIllegalTokenValue, 8, 11},
+		{`summary~""`, ErrIllegalTokenValue, 0, 10},
+		{`summary~"foo`, ErrUnrecognisedToken, 0, 1},
+		{"weekday:foo", ErrIllegalTokenValue, 0, 11},
+		{"#foo && weekday:mon...", ErrIllegalTokenValue, 8, 14},
+		{"weekday:mon...tue...wed", ErrIllegalTokenValue, 0, 23},
+		{"duration>4x", ErrIllegalTokenValue, 9, 2},
+		{"type:range && duration<=1:30", ErrIllegalTokenValue, 24, 4},
+		{"duration=", ErrIllegalTokenValue, 9, 1},
+		{"duration=>1h", ErrIllegalTokenValue, 9, 3},
+		{"start>=25:00", ErrIllegalTokenValue, 7, 5},
+		{"#foo || end<7", ErrIllegalTokenValue, 12, 1},
+		{"end=<1:00>", ErrIllegalTokenValue, 4, 6},
+		{"start>", ErrIllegalTokenValue, 6, 1},
 		{"foo", ErrUnrecognisedToken, 0, 1},
+		{"#foo && @bar", ErrUnknownNamedFilter, 8, 4},
+		{"diff<1", ErrIllegalTokenValue, 5, 1},
+		{"diff>", ErrIllegalTokenValue, 5, 1},
+		{"has:foo", ErrIllegalTokenValue, 0, 7},
 	} {
 		t.Run(tt.input, func(t *testing.T) {
 			p, err := Parse(tt.input)
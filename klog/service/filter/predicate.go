@@ -2,9 +2,11 @@ package filter
 
 import (
 	"fmt"
+	"regexp"
 	"strings"
 
 	"github.com/jotaen/klog/klog"
+	"github.com/jotaen/klog/klog/service"
 )
 
 // Predicate is the generic base type for all predicates. The caller is responsible for
@@ -42,16 +44,120 @@ func (i IsInDateRange) MatchesEmptyRecord(r klog.Record) bool {
 	return isAfter && isBefore
 }
 
+var weekdayNames = []string{"monday", "tuesday", "wednesday", "thursday", "friday", "saturday", "sunday"}
+
+// NewWeekdayFromString parses the name of a weekday (regardless of the letter
+// case), either in full or as three-letter abbreviation, e.g. `monday` or
+// `mon`. It returns the day of the week, starting from Monday = 1.
+func NewWeekdayFromString(val string) (int, error) {
+	val = strings.ToLower(val)
+	for i, name := range weekdayNames {
+		if val == name || val == name[:3] {
+			return i + 1, nil
+		}
+	}
+	return 0, fmt.Errorf("%s is not a valid weekday", val)
+}
+
+// IsOnWeekday matches if the record date is on a weekday within the range
+// (inclusive). The weekdays start from Monday = 1. The range may wrap around
+// the end of the week, e.g. from Friday (5) to Monday (1).
+type IsOnWeekday struct {
+	From int
+	To   int
+}
+
+func (i IsOnWeekday) Matches(r klog.Record, e klog.Entry) bool {
+	return i.MatchesEmptyRecord(r)
+}
+
+func (i IsOnWeekday) MatchesEmptyRecord(r klog.Record) bool {
+	wd := r.Date().Weekday()
+	if i.From <= i.To {
+		return i.From <= wd && wd <= i.To
+	}
+	return wd >= i.From || wd <= i.To
+}
+
+// HasTag matches if there is the tag. A tag without value also matches that
+// tag with any value, and it matches its child tags as well, e.g. `#acme`
+// matches `#acme=1` and `#acme/backend`.
 type HasTag struct {
 	Tag klog.Tag
 }
 
 func (h HasTag) Matches(r klog.Record, e klog.Entry) bool {
-	return h.MatchesEmptyRecord(r) || e.Summary().Tags().Contains(h.Tag)
+	return h.MatchesEmptyRecord(r) || containsTag(e.Summary().Tags(), h.matches)
 }
 
 func (h HasTag) MatchesEmptyRecord(r klog.Record) bool {
-	return r.Summary().Tags().Contains(h.Tag)
+	return containsTag(r.Summary().Tags(), h.matches)
+}
+
+func (h HasTag) matches(t klog.Tag) bool {
+	return t == h.Tag
+}
+
+// HasTagPattern matches if there is a tag that satisfies the pattern, e.g.
+// `#client-*`. It matches in the same way as HasTag.
+type HasTagPattern struct {
+	Pattern klog.TagPattern
+}
+
+func (h HasTagPattern) Matches(r klog.Record, e klog.Entry) bool {
+	return h.MatchesEmptyRecord(r) || containsTag(e.Summary().Tags(), h.Pattern.Matches)
+}
+
+func (h HasTagPattern) MatchesEmptyRecord(r klog.Record) bool {
+	return containsTag(r.Summary().Tags(), h.Pattern.Matches)
+}
+
+// containsTag checks whether any of the tags, or any of their parents,
+// satisfies the match function.
+func containsTag(ts *klog.TagSet, matches func(klog.Tag) bool) bool {
+	for t := range ts.ForLookup() {
+		if matches(t) {
+			return true
+		}
+		for _, p := range t.Parents() {
+			if matches(p) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// SummaryContains matches if the summary contains the text, regardless of
+// the letter case. Line breaks in the summary are treated as spaces.
+type SummaryContains struct {
+	Text string
+}
+
+func (s SummaryContains) Matches(r klog.Record, e klog.Entry) bool {
+	return s.MatchesEmptyRecord(r) || s.contains(e.Summary().Lines())
+}
+
+func (s SummaryContains) MatchesEmptyRecord(r klog.Record) bool {
+	return s.contains(r.Summary().Lines())
+}
+
+func (s SummaryContains) contains(lines []string) bool {
+	return strings.Contains(strings.ToLower(strings.Join(lines, " ")), strings.ToLower(s.Text))
+}
+
+// SummaryMatches matches if the summary matches the regular expression.
+// Line breaks in the summary are treated as spaces.
+type SummaryMatches struct {
+	Pattern *regexp.Regexp
+}
+
+func (s SummaryMatches) Matches(r klog.Record, e klog.Entry) bool {
+	return s.MatchesEmptyRecord(r) || s.Pattern.MatchString(strings.Join(e.Summary().Lines(), " "))
+}
+
+func (s SummaryMatches) MatchesEmptyRecord(r klog.Record) bool {
+	return s.Pattern.MatchString(strings.Join(r.Summary().Lines(), " "))
 }
 
 type And struct {
@@ -110,6 +216,134 @@ func (n Not) MatchesEmptyRecord(r klog.Record) bool {
 	return !n.Predicate.MatchesEmptyRecord(r)
 }
 
+// Comparator is a relational operator for comparing the value of an entry
+// with the operand value.
+type Comparator string
+
+const (
+	COMPARE_LESS             = Comparator("<")
+	COMPARE_LESS_OR_EQUAL    = Comparator("<=")
+	COMPARE_GREATER          = Comparator(">")
+	COMPARE_GREATER_OR_EQUAL = Comparator(">=")
+	COMPARE_EQUAL            = Comparator("=")
+)
+
+func (c Comparator) compare(value int, operand int) bool {
+	switch c {
+	case COMPARE_LESS:
+		return value < operand
+	case COMPARE_LESS_OR_EQUAL:
+		return value <= operand
+	case COMPARE_GREATER:
+		return value > operand
+	case COMPARE_GREATER_OR_EQUAL:
+		return value >= operand
+	case COMPARE_EQUAL:
+		return value == operand
+	}
+	return false
+}
+
+// HasDuration matches entries whose duration satisfies the comparison with
+// the given duration. Open ranges never match, as their duration is unknown.
+type HasDuration struct {
+	Comparator Comparator
+	Duration   klog.Duration
+}
+
+func (h HasDuration) Matches(r klog.Record, e klog.Entry) bool {
+	return klog.Unbox[bool](&e, func(r klog.Range) bool {
+		return h.Comparator.compare(r.Duration().InMinutes(), h.Duration.InMinutes())
+	}, func(d klog.Duration) bool {
+		return h.Comparator.compare(d.InMinutes(), h.Duration.InMinutes())
+	}, func(o klog.OpenRange) bool {
+		return false
+	})
+}
+
+func (h HasDuration) MatchesEmptyRecord(r klog.Record) bool {
+	return false
+}
+
+// HasStartTime matches ranges and open ranges whose start time satisfies the
+// comparison with the given time. Shifted times are compared in relation to
+// the record date, so e.g. `<23:00` is before `0:00`, and `1:00>` is after
+// `23:00`.
+type HasStartTime struct {
+	Comparator Comparator
+	Time       klog.Time
+}
+
+func (h HasStartTime) Matches(r klog.Record, e klog.Entry) bool {
+	return klog.Unbox[bool](&e, func(r klog.Range) bool {
+		return h.Comparator.compare(r.Start().MidnightOffset().InMinutes(), h.Time.MidnightOffset().InMinutes())
+	}, func(d klog.Duration) bool {
+		return false
+	}, func(o klog.OpenRange) bool {
+		return h.Comparator.compare(o.Start().MidnightOffset().InMinutes(), h.Time.MidnightOffset().InMinutes())
+	})
+}
+
+func (h HasStartTime) MatchesEmptyRecord(r klog.Record) bool {
+	return false
+}
+
+// HasEndTime matches ranges whose end time satisfies the comparison with the
+// given time. Shifted times are compared in the same way as for HasStartTime.
+// Open ranges never match, as their end time is unknown.
+type HasEndTime struct {
+	Comparator Comparator
+	Time       klog.Time
+}
+
+func (h HasEndTime) Matches(r klog.Record, e klog.Entry) bool {
+	return klog.Unbox[bool](&e, func(r klog.Range) bool {
+		return h.Comparator.compare(r.End().MidnightOffset().InMinutes(), h.Time.MidnightOffset().InMinutes())
+	}, func(d klog.Duration) bool {
+		return false
+	}, func(o klog.OpenRange) bool {
+		return false
+	})
+}
+
+func (h HasEndTime) MatchesEmptyRecord(r klog.Record) bool {
+	return false
+}
+
+// HasShouldTotal matches records that have a should-total. It applies to all
+// entries of the record.
+type HasShouldTotal struct{}
+
+func (h HasShouldTotal) Matches(r klog.Record, e klog.Entry) bool {
+	return h.MatchesEmptyRecord(r)
+}
+
+func (h HasShouldTotal) MatchesEmptyRecord(r klog.Record) bool {
+	return r.ShouldTotal().InMinutes() != 0
+}
+
+// HasDiff matches records whose difference between the actual total and the
+// should-total satisfies the comparison with the given duration. Records
+// without should-total never match. It applies to all entries of the record,
+// whereby the difference is always evaluated for the entire record, i.e.
+// regardless of whether entries are filtered out by other predicates.
+type HasDiff struct {
+	Comparator Comparator
+	Diff       klog.Duration
+}
+
+func (h HasDiff) Matches(r klog.Record, e klog.Entry) bool {
+	return h.MatchesEmptyRecord(r)
+}
+
+func (h HasDiff) MatchesEmptyRecord(r klog.Record) bool {
+	if !(HasShouldTotal{}).MatchesEmptyRecord(r) {
+		return false
+	}
+	diff := service.Diff(r.ShouldTotal(), service.Total(r))
+	return h.Comparator.compare(diff.InMinutes(), h.Diff.InMinutes())
+}
+
 type EntryType string
 
 const (
@@ -18,6 +18,14 @@ const (
 	tokenDateRange
 	tokenTag
 	tokenEntryType
+	tokenSummary
+	tokenDuration
+	tokenStartTime
+	tokenEndTime
+	tokenWeekday
+	tokenNamedFilter
+	tokenDiff
+	tokenHas
 )
 
 type token struct {
@@ -27,11 +35,18 @@ type token struct {
 }
 
 var (
-	tagRegex       = regexp.MustCompile(`^(#([\p{L}\d_-]+)(=(("[^"]*")|('[^']*')|([\p{L}\d_-]*)))?)`)
-	dateRangeRegex = regexp.MustCompile(`^(((\d{4}-\d{2}-\d{2})|(\d{4}-\p{L}?\d+)|(\d{4}))?\.{3}((\d{4}-\d{2}-\d{2})|(\d{4}-\p{L}?\d+)|(\d{4}))?)`)
-	dateRegex      = regexp.MustCompile(`^(\d{4}-\d{2}-\d{2})`)
+	tagRegex       = regexp.MustCompile(`^(#([\p{L}\d_*-]+(?:/[\p{L}\d_*-]+)*)(=(("[^"]*")|('[^']*')|([\p{L}\d_*-]*)))?)`)
+	dateRangeRegex = regexp.MustCompile(`^(((\d{4}-\d{2}-\d{2})|(\d{4}-\p{L}?\d+)|(\d{4})|(today|yesterday|[+-]\d+[dw]))?\.{3}((\d{4}-\d{2}-\d{2})|(\d{4}-\p{L}?\d+)|(\d{4})|(today|yesterday|[+-]\d+[dw]))?)`)
+	dateRegex      = regexp.MustCompile(`^((\d{4}-\d{2}-\d{2})|today|yesterday|[+-]\d+[dw])`)
 	periodRegex    = regexp.MustCompile(`^((\d{4}-\p{L}?\d+)|(\d{4}))`)
 	typeRegex      = regexp.MustCompile(`^(type:[\p{L}\-_]+)`)
+	weekdayRegex   = regexp.MustCompile(`^(weekday:[\p{L}.]+)`)
+	durationRegex  = regexp.MustCompile(`^(duration(<=|>=|<|>|=)[^\s()&|!]*)`)
+	diffRegex      = regexp.MustCompile(`^(diff(<=|>=|<|>|=)[^\s()&|!]*)`)
+	hasRegex       = regexp.MustCompile(`^(has:[\p{L}\-_]+)`)
+	timeRegex      = regexp.MustCompile(`^((start|end)(<=|>=|<|>|=)[^\s()&|!]*)`)
+	namedRegex     = regexp.MustCompile(`^(@[\p{L}\d_-]+)`)
+	summaryRegex   = regexp.MustCompile(`^(summary~(("[^"]*")|('[^']*')|(/(\\.|[^/\\])*/)|([^\s()"'/]+)))`)
 )
 
 var (
@@ -106,6 +121,80 @@ func tokenise(filterQuery string) ([]token, ParseError) {
 					length:   1,
 				}
 			}
+		} else if wm := txtParser.peekRegex(weekdayRegex); wm != nil {
+			tokens = append(tokens, token{tokenWeekday, wm[1], txtParser.pointer})
+			txtParser.advance(len(wm[1]))
+			if !txtParser.peekString(EOT, " ", ")") {
+				return nil, parseError{
+					err:      ErrMissingWhiteSpace,
+					position: txtParser.pointer,
+					length:   1,
+				}
+			}
+		} else if dm := txtParser.peekRegex(durationRegex); dm != nil {
+			tokens = append(tokens, token{tokenDuration, dm[1], txtParser.pointer})
+			txtParser.advance(len(dm[1]))
+			if !txtParser.peekString(EOT, " ", ")") {
+				return nil, parseError{
+					err:      ErrMissingWhiteSpace,
+					position: txtParser.pointer,
+					length:   1,
+				}
+			}
+		} else if dm := txtParser.peekRegex(diffRegex); dm != nil {
+			tokens = append(tokens, token{tokenDiff, dm[1], txtParser.pointer})
+			txtParser.advance(len(dm[1]))
+			if !txtParser.peekString(EOT, " ", ")") {
+				return nil, parseError{
+					err:      ErrMissingWhiteSpace,
+					position: txtParser.pointer,
+					length:   1,
+				}
+			}
+		} else if hm := txtParser.peekRegex(hasRegex); hm != nil {
+			tokens = append(tokens, token{tokenHas, hm[1], txtParser.pointer})
+			txtParser.advance(len(hm[1]))
+			if !txtParser.peekString(EOT, " ", ")") {
+				return nil, parseError{
+					err:      ErrMissingWhiteSpace,
+					position: txtParser.pointer,
+					length:   1,
+				}
+			}
+		} else if tm := txtParser.peekRegex(timeRegex); tm != nil {
+			kind := tokenStartTime
+			if tm[2] == "end" {
+				kind = tokenEndTime
+			}
+			tokens = append(tokens, token{kind, tm[1], txtParser.pointer})
+			txtParser.advance(len(tm[1]))
+			if !txtParser.peekString(EOT, " ", ")") {
+				return nil, parseError{
+					err:      ErrMissingWhiteSpace,
+					position: txtParser.pointer,
+					length:   1,
+				}
+			}
+		} else if nm := txtParser.peekRegex(namedRegex); nm != nil {
+			tokens = append(tokens, token{tokenNamedFilter, nm[1], txtParser.pointer})
+			txtParser.advance(len(nm[1]))
+			if !txtParser.peekString(EOT, " ", ")") {
+				return nil, parseError{
+					err:      ErrMissingWhiteSpace,
+					position: txtParser.pointer,
+					length:   1,
+				}
+			}
+		} else if sm := txtParser.peekRegex(summaryRegex); sm != nil {
+			tokens = append(tokens, token{tokenSummary, sm[1], txtParser.pointer})
+			txtParser.advance(len(sm[1]))
+			if !txtParser.peekString(EOT, " ", ")") {
+				return nil, parseError{
+					err:      ErrMissingWhiteSpace,
+					position: txtParser.pointer,
+					length:   1,
+				}
+			}
 		} else if rm := txtParser.peekRegex(dateRangeRegex); rm != nil {
 			value := rm[1]
 			tokens = append(tokens, token{tokenDateRange, value, txtParser.pointer})
@@ -58,12 +58,151 @@ func TestDisregardWhitespaceBetweenTokens(t *testing.T) {
 	}, p)
 }
 
+func TestTokeniseRelativeDates(t *testing.T) {
+	p, err := tokenise(`today || -14d... || (yesterday...today && !-2w) || ...+1d`)
+	require.Nil(t, err)
+	assert.Equal(t, []token{
+		{tokenDate, "today", 0},
+		{tokenOr, "||", 6},
+		{tokenDateRange, "-14d...", 9},
+		{tokenOr, "||", 17},
+		{tokenOpenBracket, "(", 20},
+		{tokenDateRange, "yesterday...today", 21},
+		{tokenAnd, "&&", 39},
+		{tokenNot, "!", 42},
+		{tokenDate, "-2w", 43},
+		{tokenCloseBracket, ")", 46},
+		{tokenOr, "||", 48},
+		{tokenDateRange, "...+1d", 51},
+	}, p)
+}
+
+func TestTokeniseTagPatterns(t *testing.T) {
+	p, err := tokenise(`#client-* && (#ticket=PROJ-* || #*=*)`)
+	require.Nil(t, err)
+	assert.Equal(t, []token{
+		{tokenTag, "#client-*", 0},
+		{tokenAnd, "&&", 10},
+		{tokenOpenBracket, "(", 13},
+		{tokenTag, "#ticket=PROJ-*", 14},
+		{tokenOr, "||", 29},
+		{tokenTag, "#*=*", 32},
+		{tokenCloseBracket, ")", 36},
+	}, p)
+}
+
+func TestTokeniseHierarchicalTags(t *testing.T) {
+	p, err := tokenise(`#acme/backend || #acme/*=v*`)
+	require.Nil(t, err)
+	assert.Equal(t, []token{
+		{tokenTag, "#acme/backend", 0},
+		{tokenOr, "||", 14},
+		{tokenTag, "#acme/*=v*", 17},
+	}, p)
+}
+
+func TestTokeniseSummary(t *testing.T) {
+	p, err := tokenise(`summary~foo && (summary~"a (b)" || summary~'c' || summary~/d e\/(f)/)`)
+	require.Nil(t, err)
+	assert.Equal(t, []token{
+		{tokenSummary, "summary~foo", 0},
+		{tokenAnd, "&&", 12},
+		{tokenOpenBracket, "(", 15},
+		{tokenSummary, `summary~"a (b)"`, 16},
+		{tokenOr, "||", 32},
+		{tokenSummary, "summary~'c'", 35},
+		{tokenOr, "||", 47},
+		{tokenSummary, `summary~/d e\/(f)/`, 50},
+		{tokenCloseBracket, ")", 68},
+	}, p)
+}
+
+func TestTokeniseDuration(t *testing.T) {
+	p, err := tokenise(`duration<30m || (duration>=4h && duration<=1h30m) || duration>0m || duration=-1h`)
+	require.Nil(t, err)
+	assert.Equal(t, []token{
+		{tokenDuration, "duration<30m", 0},
+		{tokenOr, "||", 13},
+		{tokenOpenBracket, "(", 16},
+		{tokenDuration, "duration>=4h", 17},
+		{tokenAnd, "&&", 30},
+		{tokenDuration, "duration<=1h30m", 33},
+		{tokenCloseBracket, ")", 48},
+		{tokenOr, "||", 50},
+		{tokenDuration, "duration>0m", 53},
+		{tokenOr, "||", 65},
+		{tokenDuration, "duration=-1h", 68},
+	}, p)
+}
+
+func TestTokeniseTime(t *testing.T) {
+	p, err := tokenise(`start>=18:00 || (end<7:00 && !start<<23:00) || end>1:00>`)
+	require.Nil(t, err)
+	assert.Equal(t, []token{
+		{tokenStartTime, "start>=18:00", 0},
+		{tokenOr, "||", 13},
+		{tokenOpenBracket, "(", 16},
+		{tokenEndTime, "end<7:00", 17},
+		{tokenAnd, "&&", 26},
+		{tokenNot, "!", 29},
+		{tokenStartTime, "start<<23:00", 30},
+		{tokenCloseBracket, ")", 42},
+		{tokenOr, "||", 44},
+		{tokenEndTime, "end>1:00>", 47},
+	}, p)
+}
+
+func TestTokeniseWeekday(t *testing.T) {
+	p, err := tokenise(`weekday:sat || (weekday:Mon...fri && !weekday:wednesday)`)
+	require.Nil(t, err)
+	assert.Equal(t, []token{
+		{tokenWeekday, "weekday:sat", 0},
+		{tokenOr, "||", 12},
+		{tokenOpenBracket, "(", 15},
+		{tokenWeekday, "weekday:Mon...fri", 16},
+		{tokenAnd, "&&", 34},
+		{tokenNot, "!", 37},
+		{tokenWeekday, "weekday:wednesday", 38},
+		{tokenCloseBracket, ")", 55},
+	}, p)
+}
+
+func TestTokeniseNamedFilter(t *testing.T) {
+	p, err := tokenise(`@billable && !(@weekend || @long_days)`)
+	require.Nil(t, err)
+	assert.Equal(t, []token{
+		{tokenNamedFilter, "@billable", 0},
+		{tokenAnd, "&&", 10},
+		{tokenNot, "!", 13},
+		{tokenOpenBracket, "(", 14},
+		{tokenNamedFilter, "@weekend", 15},
+		{tokenOr, "||", 24},
+		{tokenNamedFilter, "@long_days", 27},
+		{tokenCloseBracket, ")", 37},
+	}, p)
+}
+
+func TestTokeniseDiffAndHas(t *testing.T) {
+	p, err := tokenise(`has:should && (diff<0 || diff>=+1h)`)
+	require.Nil(t, err)
+	assert.Equal(t, []token{
+		{tokenHas, "has:should", 0},
+		{tokenAnd, "&&", 11},
+		{tokenOpenBracket, "(", 14},
+		{tokenDiff, "diff<0", 15},
+		{tokenOr, "||", 22},
+		{tokenDiff, "diff>=+1h", 25},
+		{tokenCloseBracket, ")", 34},
+	}, p)
+}
+
 func TestFailsOnUnrecognisedToken(t *testing.T) {
 	for _, txt := range []string{
 		"abcde",
 		"2020-01-01 & 2020-01-02",
 		"2020-01-01 * 2020-01-02",
 		"2020-01-01 {2020-01-02}",
+		"2020-01-01 || @",
 	} {
 		t.Run(txt, func(t *testing.T) {
 			p, err := tokenise(txt)
@@ -101,6 +240,10 @@ func TestFailsOnMissingWhitespace(t *testing.T) {
 		"2020-01-01...2020-01-31( #foo )",
 		"2020-01-01...&&",
 		"2020-01-01...( #foo )",
+		"today&&",
+		"-14d...||",
+		"yesterday( #foo )",
+		"-2weeks",
 
 		"(2021-12-12 || #foo)2020-01-01",
 		"(2021-12-12 || #foo)&& #foo",
@@ -119,6 +262,31 @@ func TestFailsOnMissingWhitespace(t *testing.T) {
 		"type:duration||",
 		"type:duration( 2020-01-01 )",
 		"type:duration!( 2020-01-01 )",
+
+		"weekday:mon&&",
+		"weekday:mon...fri||",
+		"weekday:sat( 2020-01-01 )",
+		"weekday:sun!( 2020-01-01 )",
+
+		"diff<0&&",
+		"diff>=1h||",
+		"has:should( 2020-01-01 )",
+		"has:should!( 2020-01-01 )",
+
+		"@billable&&",
+		"@billable||",
+		"@billable( 2020-01-01 )",
+		"@billable!( 2020-01-01 )",
+
+		"duration>4h&&",
+		"duration<=30m||",
+		"duration=1h( 2020-01-01 )",
+		"duration>0m!( 2020-01-01 )",
+
+		"start>=18:00&&",
+		"end<7:00||",
+		"start<<23:00( 2020-01-01 )",
+		"end>1:00>!( 2020-01-01 )",
 	} {
 		t.Run(txt, func(t *testing.T) {
 			p, err := tokenise(txt)
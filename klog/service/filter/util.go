@@ -82,7 +82,7 @@ func (t *tokenParser) checkNextIsOperand() ParseError {
 		}
 	}
 	for _, k := range []tokenKind{
-		tokenOpenBracket, tokenTag, tokenDate, tokenDateRange, tokenPeriod, tokenNot, tokenEntryType,
+		tokenOpenBracket, tokenTag, tokenDate, tokenDateRange, tokenPeriod, tokenNot, tokenEntryType, tokenSummary, tokenDuration, tokenStartTime, tokenEndTime, tokenWeekday, tokenNamedFilter, tokenDiff, tokenHas,
 	} {
 		if t.tokens[t.pointer].kind == k {
 			return nil
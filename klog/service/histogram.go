@@ -0,0 +1,67 @@
+package service
+
+import (
+	"github.com/jotaen/klog/klog"
+)
+
+// TimeOfDayHistogram contains the tracked minutes per weekday and hour of day.
+// The first index is the weekday (where 0 is Monday and 6 is Sunday), the
+// second index is the hour of day.
+type TimeOfDayHistogram [7][24]int
+
+// NewTimeOfDayHistogram distributes the minutes of all time ranges into
+// buckets by weekday and hour of day. Ranges are split at the bucket
+// boundaries, and shifted times are attributed to the previous or next day,
+// respectively. E.g., the range `<23:30 - 0:15` in a record of a Tuesday
+// yields 30 minutes on Monday 23:00, and 15 minutes on Tuesday 0:00.
+// Durations are not taken into account, as they don’t have a time of day,
+// and neither are open ranges.
+func NewTimeOfDayHistogram(rs ...klog.Record) TimeOfDayHistogram {
+	const ONE_DAY = 24 * 60
+	var h TimeOfDayHistogram
+	for _, r := range rs {
+		for _, e := range r.Entries() {
+			tr := klog.Unbox[klog.Range](&e,
+				func(tr klog.Range) klog.Range { return tr },
+				func(_ klog.Duration) klog.Range { return nil },
+				func(_ klog.OpenRange) klog.Range { return nil },
+			)
+			if tr == nil {
+				continue
+			}
+			start := tr.Start().MidnightOffset().InMinutes()
+			end := tr.End().MidnightOffset().InMinutes()
+			for start < end {
+				// Shift the minutes into [0, ONE_DAY) and keep track of the day shift.
+				dayShift := floorDiv(start, ONE_DAY)
+				minuteOfDay := start - dayShift*ONE_DAY
+				bucketEnd := start + 60 - minuteOfDay%60
+				if bucketEnd > end {
+					bucketEnd = end
+				}
+				weekday := ((r.Date().Weekday()-1+dayShift)%7 + 7) % 7
+				h[weekday][minuteOfDay/60] += bucketEnd - start
+				start = bucketEnd
+			}
+		}
+	}
+	return h
+}
+
+// ByHour returns the tracked minutes per hour of day, across all weekdays.
+func (h TimeOfDayHistogram) ByHour() [24]int {
+	var result [24]int
+	for _, hours := range h {
+		for hour, mins := range hours {
+			result[hour] += mins
+		}
+	}
+	return result
+}
+
+func floorDiv(a int, b int) int {
+	if a < 0 && a%b != 0 {
+		return a/b - 1
+	}
+	return a / b
+}
@@ -0,0 +1,56 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/jotaen/klog/klog"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTimeOfDayHistogramOfNoRecords(t *testing.T) {
+	h := NewTimeOfDayHistogram()
+	assert.Equal(t, TimeOfDayHistogram{}, h)
+	assert.Equal(t, [24]int{}, h.ByHour())
+}
+
+func TestTimeOfDayHistogramSplitsRangesIntoHours(t *testing.T) {
+	// Monday
+	r := klog.NewRecord(klog.Ɀ_Date_(2024, 1, 1))
+	r.AddRange(klog.Ɀ_Range_(klog.Ɀ_Time_(8, 45), klog.Ɀ_Time_(10, 15)), nil)
+	r.AddRange(klog.Ɀ_Range_(klog.Ɀ_Time_(10, 30), klog.Ɀ_Time_(11, 0)), nil)
+	r.AddDuration(klog.NewDuration(5, 0), nil)
+	_ = r.Start(klog.NewOpenRange(klog.Ɀ_Time_(15, 0)), nil)
+
+	h := NewTimeOfDayHistogram(r)
+	expected := TimeOfDayHistogram{}
+	expected[0][8] = 15
+	expected[0][9] = 60
+	expected[0][10] = 45
+	assert.Equal(t, expected, h)
+	assert.Equal(t, [24]int{8: 15, 9: 60, 10: 45}, h.ByHour())
+}
+
+func TestTimeOfDayHistogramSplitsRangesAtDayBoundaries(t *testing.T) {
+	rs := []klog.Record{
+		func() klog.Record {
+			// Monday, starting on Sunday
+			r := klog.NewRecord(klog.Ɀ_Date_(2024, 1, 1))
+			r.AddRange(klog.Ɀ_Range_(klog.Ɀ_TimeYesterday_(23, 30), klog.Ɀ_Time_(0, 15)), nil)
+			return r
+		}(),
+		func() klog.Record {
+			// Sunday, ending on Monday
+			r := klog.NewRecord(klog.Ɀ_Date_(2024, 1, 7))
+			r.AddRange(klog.Ɀ_Range_(klog.Ɀ_Time_(22, 0), klog.Ɀ_TimeTomorrow_(1, 10)), nil)
+			return r
+		}(),
+	}
+	h := NewTimeOfDayHistogram(rs...)
+	expected := TimeOfDayHistogram{}
+	expected[6][22] = 60
+	expected[6][23] = 60 + 30
+	expected[0][0] = 60 + 15
+	expected[0][1] = 10
+	assert.Equal(t, expected, h)
+	assert.Equal(t, [24]int{0: 75, 1: 10, 22: 60, 23: 90}, h.ByHour())
+}
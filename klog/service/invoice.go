@@ -0,0 +1,222 @@
+package service
+
+import (
+	"errors"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/jotaen/klog/klog"
+)
+
+// Amount is a monetary value in hundredths of the currency unit (e.g., cents).
+type Amount int
+
+var amountPattern = regexp.MustCompile(`^(\d+)(?:\.(\d{1,2}))?$`)
+
+// NewAmountFromString parses a non-negative decimal number with up to two
+// decimal places, e.g. `120` or `95.50`.
+func NewAmountFromString(value string) (Amount, error) {
+	match := amountPattern.FindStringSubmatch(value)
+	if match == nil {
+		return 0, errors.New("INVALID_AMOUNT")
+	}
+	units, err := strconv.Atoi(match[1])
+	if err != nil {
+		return 0, errors.New("INVALID_AMOUNT")
+	}
+	hundredths, _ := strconv.Atoi((match[2] + "00")[:2])
+	return Amount(units*100 + hundredths), nil
+}
+
+// ToString serialises the amount with two decimal places, e.g. `95.50`.
+func (a Amount) ToString() string {
+	sign := ""
+	if a < 0 {
+		sign = "-"
+		a = -a
+	}
+	return sign + strconv.Itoa(int(a)/100) + "." + strconv.Itoa(int(a)%100/10) + strconv.Itoa(int(a)%10)
+}
+
+// Rate is the hourly rate for all entries that match a tag.
+type Rate struct {
+	Tag     klog.Tag
+	PerHour Amount
+}
+
+// Bill returns the amount for the given duration, rounded to the nearest
+// hundredth.
+func (r Rate) Bill(d klog.Duration) Amount {
+	return Amount(math.Round(float64(d.InMinutes()) * float64(r.PerHour) / 60))
+}
+
+// NewRatesFromString parses a list of rates, which are separated by commas
+// or by line breaks. Every rate consists of a tag and an amount, e.g.:
+// `#acme 120, #project=beta 95.50`. Blank items are ignored.
+func NewRatesFromString(text string) ([]Rate, error) {
+	var rates []Rate
+	seen := make(map[klog.Tag]bool)
+	for _, item := range strings.FieldsFunc(text, func(c rune) bool { return c == ',' || c == '\n' }) {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		match := klog.HashTagPattern.FindStringIndex(item)
+		if match == nil || match[0] != 0 {
+			return nil, errors.New("Invalid rate `" + item + "`: it must start with a tag")
+		}
+		tag, tErr := klog.NewTagFromString(item[:match[1]])
+		if tErr != nil {
+			return nil, errors.New("Invalid rate `" + item + "`: the tag is malformed")
+		}
+		amount, aErr := NewAmountFromString(strings.TrimSpace(item[match[1]:]))
+		if aErr != nil {
+			return nil, errors.New("Invalid rate `" + item + "`: the amount must be a number, e.g. `120` or `95.50`")
+		}
+		if seen[tag] {
+			return nil, errors.New("Duplicate rate for `" + tag.ToString() + "`")
+		}
+		seen[tag] = true
+		rates = append(rates, Rate{Tag: tag, PerHour: amount})
+	}
+	return rates, nil
+}
+
+// RoundUp rounds the duration up to the next multiple of the rounding value.
+// Negative durations are rounded in the same way with regard to their
+// absolute value. E.g. for rounding=15m: 20m => 30m, -20m => -30m.
+func RoundUp(d klog.Duration, r Rounding) klog.Duration {
+	mins := d.InMinutes()
+	v := r.ToInt()
+	sign := 1
+	if mins < 0 {
+		sign = -1
+		mins = -mins
+	}
+	if mins%v != 0 {
+		mins += v - mins%v
+	}
+	return klog.NewDuration(0, sign*mins)
+}
+
+// InvoiceItem is the billed time of a rated tag at one date.
+type InvoiceItem struct {
+	Date   klog.Date
+	Total  klog.Duration
+	Amount Amount
+}
+
+// InvoiceSection contains all items that are billed at the same rate.
+type InvoiceSection struct {
+	Rate   Rate
+	Items  []InvoiceItem
+	Total  klog.Duration
+	Amount Amount
+}
+
+type Invoice struct {
+	Sections []InvoiceSection
+	Total    klog.Duration
+	Amount   Amount
+
+	// Unbilled is the total time of all entries that don’t match any rate.
+	Unbilled klog.Duration
+}
+
+// NewInvoice bills the entries of the records according to the rates, and
+// itemises the result per rate and date. If a rounding is given, the
+// duration of every entry is rounded up before it’s billed. It returns an
+// error if an entry matches more than one rate, since the entry would be
+// billed twice then.
+// The entries are matched against the rates directly, i.e. hierarchical tags
+// are not rolled up to their parents (unlike in AggregateTotalsByTags), as
+// otherwise rates for both `#acme` and `#acme/backend` would bill twice.
+func NewInvoice(rs []klog.Record, rates []Rate, r Rounding) (Invoice, error) {
+	invoice := Invoice{
+		Sections: make([]InvoiceSection, len(rates)),
+		Total:    klog.NewDuration(0, 0),
+		Unbilled: klog.NewDuration(0, 0),
+	}
+	for i, rate := range rates {
+		invoice.Sections[i] = InvoiceSection{Rate: rate, Total: klog.NewDuration(0, 0)}
+	}
+	dates, recordsByDate := groupByDate(rs)
+	for _, date := range dates {
+		// The totals of the day per rate, or nil if there are no entries.
+		totals := make([]klog.Duration, len(rates))
+		for _, original := range recordsByDate[dateKey(date)] {
+			for _, e := range original.Entries() {
+				matchingRate := -1
+				matchingRates := 0
+				tags := klog.Merge(original.Summary().Tags(), e.Summary().Tags())
+				for i, rate := range rates {
+					if tags.Contains(rate.Tag) {
+						matchingRate = i
+						matchingRates++
+					}
+				}
+				duration := e.Duration()
+				if r != nil {
+					duration = RoundUp(duration, r)
+				}
+				if matchingRates == 0 {
+					invoice.Unbilled = invoice.Unbilled.Plus(duration)
+					continue
+				}
+				if matchingRates > 1 {
+					return Invoice{}, errors.New("The entry `" + klog.Unbox[string](&e,
+						func(tr klog.Range) string { return tr.ToString() },
+						func(d klog.Duration) string { return d.ToString() },
+						func(o klog.OpenRange) string { return o.ToString() },
+					) + "` at " + date.ToString() + " matches more than one rate")
+				}
+				if totals[matchingRate] == nil {
+					totals[matchingRate] = klog.NewDuration(0, 0)
+				}
+				totals[matchingRate] = totals[matchingRate].Plus(duration)
+			}
+		}
+		for i, total := range totals {
+			if total == nil {
+				continue
+			}
+			section := &invoice.Sections[i]
+			item := InvoiceItem{Date: date, Total: total, Amount: section.Rate.Bill(total)}
+			section.Items = append(section.Items, item)
+			section.Total = section.Total.Plus(item.Total)
+			section.Amount += item.Amount
+		}
+	}
+	for _, section := range invoice.Sections {
+		invoice.Total = invoice.Total.Plus(section.Total)
+		invoice.Amount += section.Amount
+	}
+	return invoice, nil
+}
+
+// groupByDate returns the dates of the records (sorted, oldest first), and
+// the records grouped by date (see dateKey).
+func groupByDate(rs []klog.Record) ([]klog.Date, map[int][]klog.Record) {
+	var dates []klog.Date
+	recordsByDate := make(map[int][]klog.Record)
+	for _, r := range rs {
+		key := dateKey(r.Date())
+		if recordsByDate[key] == nil {
+			dates = append(dates, r.Date())
+		}
+		recordsByDate[key] = append(recordsByDate[key], r)
+	}
+	sort.Slice(dates, func(i, j int) bool {
+		return !dates[i].IsAfterOrEqual(dates[j])
+	})
+	return dates, recordsByDate
+}
+
+// dateKey identifies the date regardless of its notation, e.g. `2020-01-01`
+// and `2020/01/01` yield the same key.
+func dateKey(d klog.Date) int {
+	return d.Year()*10000 + d.Month()*100 + d.Day()
+}
@@ -0,0 +1,183 @@
+package service
+
+import (
+	"github.com/jotaen/klog/klog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestParseAmounts(t *testing.T) {
+	for _, x := range []struct {
+		text string
+		exp  Amount
+	}{
+		{"0", 0},
+		{"120", 12000},
+		{"95.5", 9550},
+		{"95.50", 9550},
+		{"0.05", 5},
+	} {
+		a, err := NewAmountFromString(x.text)
+		require.Nil(t, err)
+		assert.Equal(t, x.exp, a)
+	}
+	for _, text := range []string{"", "-1", "1.234", "1,5", "abc", "1."} {
+		_, err := NewAmountFromString(text)
+		assert.Error(t, err, text)
+	}
+}
+
+func TestSerialiseAmounts(t *testing.T) {
+	assert.Equal(t, "0.00", Amount(0).ToString())
+	assert.Equal(t, "0.05", Amount(5).ToString())
+	assert.Equal(t, "95.50", Amount(9550).ToString())
+	assert.Equal(t, "-1.20", Amount(-120).ToString())
+}
+
+func TestParseRates(t *testing.T) {
+	rates, err := NewRatesFromString(`#acme 120, #project=beta 95.5
+#Client="Big Corp"   80
+
+`)
+	require.Nil(t, err)
+	assert.Equal(t, []Rate{
+		{klog.NewTagOrPanic("acme", ""), 12000},
+		{klog.NewTagOrPanic("project", "beta"), 9550},
+		{klog.NewTagOrPanic("client", "Big Corp"), 8000},
+	}, rates)
+}
+
+func TestParseRatesFailsForInvalidInput(t *testing.T) {
+	for _, text := range []string{
+		"acme 120",
+		"#acme",
+		"#acme 1.234",
+		"120 #acme",
+		"#acme 120, #acme 100",
+	} {
+		_, err := NewRatesFromString(text)
+		assert.Error(t, err, text)
+	}
+}
+
+func TestRoundUpDurations(t *testing.T) {
+	for _, x := range []struct {
+		d   klog.Duration
+		r   Rounding
+		exp klog.Duration
+	}{
+		{klog.NewDuration(0, 0), r(15), klog.NewDuration(0, 0)},
+		{klog.NewDuration(0, 1), r(15), klog.NewDuration(0, 15)},
+		{klog.NewDuration(0, 15), r(15), klog.NewDuration(0, 15)},
+		{klog.NewDuration(1, 16), r(30), klog.NewDuration(1, 30)},
+		{klog.NewDuration(0, -20), r(15), klog.NewDuration(0, -30)},
+	} {
+		assert.Equal(t, x.exp, RoundUp(x.d, x.r))
+	}
+}
+
+func TestCreateInvoice(t *testing.T) {
+	rs := []klog.Record{
+		func() klog.Record {
+			r := klog.NewRecord(klog.Ɀ_Date_(2020, 1, 2))
+			r.AddDuration(klog.NewDuration(1, 10), klog.Ɀ_EntrySummary_("#acme"))
+			r.AddDuration(klog.NewDuration(0, 20), klog.Ɀ_EntrySummary_("#beta=x"))
+			r.AddDuration(klog.NewDuration(2, 0), klog.Ɀ_EntrySummary_("#beta=y"))
+			return r
+		}(),
+		func() klog.Record {
+			r := klog.NewRecord(klog.Ɀ_Date_(2020, 1, 1))
+			r.SetSummary(klog.Ɀ_RecordSummary_("#acme"))
+			r.AddDuration(klog.NewDuration(1, 0), nil)
+			r.AddDuration(klog.NewDuration(0, 5), klog.Ɀ_EntrySummary_("Call"))
+			return r
+		}(),
+		func() klog.Record {
+			r := klog.NewRecord(klog.Ɀ_Date_(2020, 1, 2))
+			r.AddDuration(klog.NewDuration(0, 50), klog.Ɀ_EntrySummary_("#acme"))
+			r.AddDuration(klog.NewDuration(0, 40), klog.Ɀ_EntrySummary_("#other"))
+			return r
+		}(),
+	}
+	rates := []Rate{
+		{klog.NewTagOrPanic("beta", "x"), 9000},
+		{klog.NewTagOrPanic("acme", ""), 12050},
+	}
+
+	t.Run("Without rounding", func(t *testing.T) {
+		invoice, err := NewInvoice(rs, rates, nil)
+		require.Nil(t, err)
+		require.Len(t, invoice.Sections, 2)
+
+		assert.Equal(t, rates[0], invoice.Sections[0].Rate)
+		assert.Equal(t, []InvoiceItem{
+			{klog.Ɀ_Date_(2020, 1, 2), klog.NewDuration(0, 20), 3000},
+		}, invoice.Sections[0].Items)
+		assert.Equal(t, klog.NewDuration(0, 20), invoice.Sections[0].Total)
+		assert.Equal(t, Amount(3000), invoice.Sections[0].Amount)
+
+		assert.Equal(t, rates[1], invoice.Sections[1].Rate)
+		assert.Equal(t, []InvoiceItem{
+			{klog.Ɀ_Date_(2020, 1, 1), klog.NewDuration(1, 5), 13054},
+			{klog.Ɀ_Date_(2020, 1, 2), klog.NewDuration(2, 0), 24100},
+		}, invoice.Sections[1].Items)
+		assert.Equal(t, klog.NewDuration(3, 5), invoice.Sections[1].Total)
+		assert.Equal(t, Amount(37154), invoice.Sections[1].Amount)
+
+		assert.Equal(t, klog.NewDuration(3, 25), invoice.Total)
+		assert.Equal(t, Amount(40154), invoice.Amount)
+		assert.Equal(t, klog.NewDuration(2, 40), invoice.Unbilled)
+	})
+
+	t.Run("With rounding", func(t *testing.T) {
+		invoice, err := NewInvoice(rs, rates, r(15))
+		require.Nil(t, err)
+		assert.Equal(t, klog.NewDuration(0, 30), invoice.Sections[0].Total)
+		assert.Equal(t, []InvoiceItem{
+			{klog.Ɀ_Date_(2020, 1, 1), klog.NewDuration(1, 15), 15063},
+			{klog.Ɀ_Date_(2020, 1, 2), klog.NewDuration(2, 15), 27113},
+		}, invoice.Sections[1].Items)
+		assert.Equal(t, klog.NewDuration(4, 0), invoice.Total)
+		assert.Equal(t, klog.NewDuration(2, 45), invoice.Unbilled)
+	})
+}
+
+func TestCreateInvoiceFailsIfEntryMatchesMultipleRates(t *testing.T) {
+	r := klog.NewRecord(klog.Ɀ_Date_(2020, 1, 1))
+	r.AddDuration(klog.NewDuration(1, 0), klog.Ɀ_EntrySummary_("#acme #beta"))
+	_, err := NewInvoice([]klog.Record{r}, []Rate{
+		{klog.NewTagOrPanic("acme", ""), 100},
+		{klog.NewTagOrPanic("beta", ""), 100},
+	}, nil)
+	require.Error(t, err)
+	assert.Equal(t, "The entry `1h` at 2020-01-01 matches more than one rate", err.Error())
+}
+
+func TestCreateInvoiceDoesNotRollUpHierarchicalTags(t *testing.T) {
+	r := klog.NewRecord(klog.Ɀ_Date_(2020, 1, 1))
+	r.AddDuration(klog.NewDuration(1, 0), klog.Ɀ_EntrySummary_("#acme"))
+	r.AddDuration(klog.NewDuration(2, 0), klog.Ɀ_EntrySummary_("#acme/x"))
+	invoice, err := NewInvoice([]klog.Record{r}, []Rate{
+		{klog.NewTagOrPanic("acme", ""), 100},
+		{klog.NewTagOrPanic("acme/x", ""), 150},
+	}, nil)
+	require.Nil(t, err)
+	assert.Equal(t, klog.NewDuration(1, 0), invoice.Sections[0].Total)
+	assert.Equal(t, klog.NewDuration(2, 0), invoice.Sections[1].Total)
+	assert.Equal(t, klog.NewDuration(3, 0), invoice.Total)
+}
+
+func TestCreateInvoiceGroupsRecordsByDateRegardlessOfNotation(t *testing.T) {
+	r1 := klog.NewRecord(klog.Ɀ_Date_(2020, 1, 1))
+	r1.AddDuration(klog.NewDuration(1, 0), klog.Ɀ_EntrySummary_("#acme"))
+	d, _ := klog.NewDateFromString("2020/01/01")
+	r2 := klog.NewRecord(d)
+	r2.AddDuration(klog.NewDuration(2, 0), klog.Ɀ_EntrySummary_("#acme"))
+	invoice, err := NewInvoice([]klog.Record{r1, r2}, []Rate{
+		{klog.NewTagOrPanic("acme", ""), 100},
+	}, nil)
+	require.Nil(t, err)
+	require.Len(t, invoice.Sections[0].Items, 1)
+	assert.Equal(t, klog.NewDuration(3, 0), invoice.Sections[0].Items[0].Total)
+}
@@ -0,0 +1,53 @@
+package period
+
+import (
+	"errors"
+	"regexp"
+	"strconv"
+	"strings"
+	gotime "time"
+
+	"github.com/jotaen/klog/klog"
+)
+
+var relativeDatePattern = regexp.MustCompile(`^([+-])(\d+)([dw])$`)
+
+// NewDateFromRelativeString resolves a relative date in relation to today.
+// The value can either be `today` or `yesterday`, or an offset of days or
+// weeks, e.g. `-14d` (14 days ago) or `-2w` (2 weeks ago).
+func NewDateFromRelativeString(value string, today klog.Date) (klog.Date, error) {
+	switch strings.ToLower(value) {
+	case "today":
+		return today, nil
+	case "yesterday":
+		return today.PlusDays(-1), nil
+	}
+	match := relativeDatePattern.FindStringSubmatch(value)
+	if match == nil {
+		return nil, errors.New("INVALID_RELATIVE_DATE")
+	}
+	amount, err := strconv.Atoi(match[2])
+	if err != nil {
+		return nil, errors.New("INVALID_RELATIVE_DATE")
+	}
+	if match[1] == "-" {
+		amount = -amount
+	}
+	if match[3] == "w" {
+		amount *= 7
+	}
+	// Not using `today.PlusDays`, since that panics if the resulting date
+	// is not representable.
+	d := gotime.Date(today.Year(), gotime.Month(today.Month()), today.Day()+amount, 0, 0, 0, 0, gotime.UTC)
+	return klog.NewDate(d.Year(), int(d.Month()), d.Day())
+}
+
+// NewDateFromAbsoluteOrRelativeString parses either an absolute date (see
+// klog.NewDateFromString) or a relative one (see NewDateFromRelativeString).
+func NewDateFromAbsoluteOrRelativeString(value string, today klog.Date) (klog.Date, error) {
+	d, err := NewDateFromRelativeString(value, today)
+	if err == nil {
+		return d, nil
+	}
+	return klog.NewDateFromString(value)
+}
@@ -0,0 +1,72 @@
+package period
+
+import (
+	"testing"
+
+	"github.com/jotaen/klog/klog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRelativeDate(t *testing.T) {
+	today := klog.Ɀ_Date_(2024, 3, 1)
+	for _, x := range []struct {
+		text   string
+		expect klog.Date
+	}{
+		{"today", klog.Ɀ_Date_(2024, 3, 1)},
+		{"Today", klog.Ɀ_Date_(2024, 3, 1)},
+		{"yesterday", klog.Ɀ_Date_(2024, 2, 29)},
+		{"-0d", klog.Ɀ_Date_(2024, 3, 1)},
+		{"-1d", klog.Ɀ_Date_(2024, 2, 29)},
+		{"-14d", klog.Ɀ_Date_(2024, 2, 16)},
+		{"+3d", klog.Ɀ_Date_(2024, 3, 4)},
+		{"-2w", klog.Ɀ_Date_(2024, 2, 16)},
+		{"+1w", klog.Ɀ_Date_(2024, 3, 8)},
+		{"-61d", klog.Ɀ_Date_(2023, 12, 31)},
+	} {
+		d, err := NewDateFromRelativeString(x.text, today)
+		require.Nil(t, err, x.text)
+		assert.Equal(t, x.expect, d, x.text)
+	}
+}
+
+func TestRejectsInvalidRelativeDate(t *testing.T) {
+	for _, text := range []string{
+		"",
+		"tomorrow",
+		"14d",
+		"-d",
+		"-2x",
+		"-2W",
+		"- 2d",
+		"-2d...",
+		"2024-03-01",
+		"-1000000d",
+		"+1000000w",
+		"-99999999999999999999d",
+	} {
+		d, err := NewDateFromRelativeString(text, klog.Ɀ_Date_(2024, 3, 1))
+		require.Error(t, err, text)
+		assert.Nil(t, d, text)
+	}
+}
+
+func TestParseAbsoluteOrRelativeDate(t *testing.T) {
+	today := klog.Ɀ_Date_(2024, 3, 1)
+	{
+		d, err := NewDateFromAbsoluteOrRelativeString("2023-11-30", today)
+		require.Nil(t, err)
+		assert.Equal(t, klog.Ɀ_Date_(2023, 11, 30), d)
+	}
+	{
+		d, err := NewDateFromAbsoluteOrRelativeString("-1w", today)
+		require.Nil(t, err)
+		assert.Equal(t, klog.Ɀ_Date_(2024, 2, 23), d)
+	}
+	{
+		d, err := NewDateFromAbsoluteOrRelativeString("foo", today)
+		require.Error(t, err)
+		assert.Nil(t, d)
+	}
+}
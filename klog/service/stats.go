@@ -0,0 +1,178 @@
+package service
+
+import (
+	"sort"
+
+	"github.com/jotaen/klog/klog"
+)
+
+// Stats contains statistics about the work patterns in the records. Only
+// days with at least one entry are taken into account; records with the
+// same date count as one day.
+type Stats struct {
+	// Days is the number of distinct days with entries.
+	Days int
+
+	Total             klog.Duration
+	AverageDailyTotal klog.Duration
+	MedianDailyTotal  klog.Duration
+
+	// TypicalStartTime is the median of the earliest start time per day.
+	// It’s nil if there are no ranges.
+	TypicalStartTime klog.Time
+
+	// TypicalEndTime is the median of the latest end time per day.
+	// It’s nil if there are no closed ranges.
+	TypicalEndTime klog.Time
+
+	// LongestStreak is the highest number of consecutive calendar days
+	// with entries.
+	LongestStreak int
+
+	// BusiestWeekday is the weekday with the highest total time (Monday
+	// is 1, Sunday is 7). It’s 0 if there are no days.
+	BusiestWeekday      int
+	BusiestWeekdayTotal klog.Duration
+
+	AverageEntriesPerDay float64
+
+	RangeCount     int
+	DurationCount  int
+	OpenRangeCount int
+}
+
+// EntryCount is the total number of entries.
+func (s Stats) EntryCount() int {
+	return s.RangeCount + s.DurationCount + s.OpenRangeCount
+}
+
+type statsDay struct {
+	date    klog.Date
+	total   klog.Duration
+	entries int
+	start   klog.Duration
+	end     klog.Duration
+}
+
+// NewStats computes the statistics for the records.
+func NewStats(rs ...klog.Record) Stats {
+	stats := Stats{
+		Total:               klog.NewDuration(0, 0),
+		AverageDailyTotal:   klog.NewDuration(0, 0),
+		MedianDailyTotal:    klog.NewDuration(0, 0),
+		BusiestWeekdayTotal: klog.NewDuration(0, 0),
+	}
+	days := make(map[string]*statsDay)
+	for _, r := range rs {
+		if len(r.Entries()) == 0 {
+			continue
+		}
+		day, ok := days[r.Date().ToString()]
+		if !ok {
+			day = &statsDay{date: r.Date(), total: klog.NewDuration(0, 0)}
+			days[r.Date().ToString()] = day
+		}
+		for _, e := range r.Entries() {
+			day.total = day.total.Plus(e.Duration())
+			day.entries++
+			var start, end klog.Time
+			klog.Unbox[any](&e, func(tr klog.Range) any {
+				stats.RangeCount++
+				start, end = tr.Start(), tr.End()
+				return nil
+			}, func(d klog.Duration) any {
+				stats.DurationCount++
+				return nil
+			}, func(o klog.OpenRange) any {
+				stats.OpenRangeCount++
+				start = o.Start()
+				return nil
+			})
+			if start != nil && (day.start == nil || start.MidnightOffset().InMinutes() < day.start.InMinutes()) {
+				day.start = start.MidnightOffset()
+			}
+			if end != nil && (day.end == nil || end.MidnightOffset().InMinutes() > day.end.InMinutes()) {
+				day.end = end.MidnightOffset()
+			}
+		}
+	}
+	if len(days) == 0 {
+		return stats
+	}
+
+	var sortedDays []*statsDay
+	for _, d := range days {
+		sortedDays = append(sortedDays, d)
+	}
+	sort.Slice(sortedDays, func(i, j int) bool {
+		return !sortedDays[i].date.IsAfterOrEqual(sortedDays[j].date)
+	})
+
+	var totals, starts, ends []int
+	weekdayTotals := make(map[int]klog.Duration)
+	streak := 0
+	for i, d := range sortedDays {
+		stats.Total = stats.Total.Plus(d.total)
+		totals = append(totals, d.total.InMinutes())
+		if d.start != nil {
+			starts = append(starts, d.start.InMinutes())
+		}
+		if d.end != nil {
+			ends = append(ends, d.end.InMinutes())
+		}
+		if weekdayTotals[d.date.Weekday()] == nil {
+			weekdayTotals[d.date.Weekday()] = klog.NewDuration(0, 0)
+		}
+		weekdayTotals[d.date.Weekday()] = weekdayTotals[d.date.Weekday()].Plus(d.total)
+		if i > 0 && sortedDays[i-1].date.PlusDays(1).IsEqualTo(d.date) {
+			streak++
+		} else {
+			streak = 1
+		}
+		if streak > stats.LongestStreak {
+			stats.LongestStreak = streak
+		}
+	}
+
+	stats.Days = len(sortedDays)
+	stats.AverageDailyTotal = klog.NewDuration(0, stats.Total.InMinutes()/stats.Days)
+	stats.MedianDailyTotal = klog.NewDuration(0, median(totals))
+	stats.AverageEntriesPerDay = float64(stats.EntryCount()) / float64(stats.Days)
+	if len(starts) > 0 {
+		stats.TypicalStartTime = timeFromMidnightOffset(median(starts))
+	}
+	if len(ends) > 0 {
+		stats.TypicalEndTime = timeFromMidnightOffset(median(ends))
+	}
+	for weekday := 1; weekday <= 7; weekday++ {
+		total, ok := weekdayTotals[weekday]
+		if !ok {
+			continue
+		}
+		if stats.BusiestWeekday == 0 || total.InMinutes() > stats.BusiestWeekdayTotal.InMinutes() {
+			stats.BusiestWeekday = weekday
+			stats.BusiestWeekdayTotal = total
+		}
+	}
+	return stats
+}
+
+// median returns the median of the values, where the median of an even number
+// of values is the (truncated) mean of the two middle ones.
+func median(values []int) int {
+	sorted := append([]int(nil), values...)
+	sort.Ints(sorted)
+	middle := len(sorted) / 2
+	if len(sorted)%2 == 1 {
+		return sorted[middle]
+	}
+	return (sorted[middle-1] + sorted[middle]) / 2
+}
+
+// timeFromMidnightOffset converts an offset in minutes (which is negative
+// for times of the previous day) into a time.
+func timeFromMidnightOffset(mins int) klog.Time {
+	midnight, _ := klog.NewTime(0, 0)
+	t, _ := midnight.Plus(klog.NewDuration(0, mins))
+	return t
+}
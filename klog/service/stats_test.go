@@ -0,0 +1,108 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/jotaen/klog/klog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStatsOfNoRecords(t *testing.T) {
+	stats := NewStats()
+	assert.Equal(t, 0, stats.Days)
+	assert.Equal(t, klog.NewDuration(0, 0), stats.Total)
+	assert.Equal(t, klog.NewDuration(0, 0), stats.AverageDailyTotal)
+	assert.Equal(t, klog.NewDuration(0, 0), stats.MedianDailyTotal)
+	assert.Nil(t, stats.TypicalStartTime)
+	assert.Nil(t, stats.TypicalEndTime)
+	assert.Equal(t, 0, stats.LongestStreak)
+	assert.Equal(t, 0, stats.BusiestWeekday)
+	assert.Equal(t, 0.0, stats.AverageEntriesPerDay)
+	assert.Equal(t, 0, stats.EntryCount())
+}
+
+func TestStats(t *testing.T) {
+	rs := []klog.Record{
+		func() klog.Record {
+			// Monday
+			r := klog.NewRecord(klog.Ɀ_Date_(2024, 1, 1))
+			r.AddRange(klog.Ɀ_Range_(klog.Ɀ_Time_(8, 0), klog.Ɀ_Time_(12, 0)), nil)
+			r.AddRange(klog.Ɀ_Range_(klog.Ɀ_Time_(13, 0), klog.Ɀ_Time_(17, 0)), nil)
+			return r
+		}(),
+		func() klog.Record {
+			// Tuesday
+			r := klog.NewRecord(klog.Ɀ_Date_(2024, 1, 2))
+			r.AddRange(klog.Ɀ_Range_(klog.Ɀ_Time_(9, 0), klog.Ɀ_Time_(11, 0)), nil)
+			r.AddDuration(klog.NewDuration(1, 0), nil)
+			return r
+		}(),
+		func() klog.Record {
+			// Tuesday, same date as above
+			r := klog.NewRecord(klog.Ɀ_Date_(2024, 1, 2))
+			r.AddRange(klog.Ɀ_Range_(klog.Ɀ_Time_(15, 0), klog.Ɀ_Time_(18, 0)), nil)
+			return r
+		}(),
+		func() klog.Record {
+			// Monday, not consecutive
+			r := klog.NewRecord(klog.Ɀ_Date_(2024, 1, 8))
+			r.AddDuration(klog.NewDuration(9, 0), nil)
+			return r
+		}(),
+		func() klog.Record {
+			// Without entries, so it’s ignored
+			return klog.NewRecord(klog.Ɀ_Date_(2024, 1, 9))
+		}(),
+	}
+	stats := NewStats(rs...)
+	assert.Equal(t, 3, stats.Days)
+	assert.Equal(t, klog.NewDuration(23, 0), stats.Total)
+	assert.Equal(t, klog.NewDuration(7, 40), stats.AverageDailyTotal)
+	assert.Equal(t, klog.NewDuration(8, 0), stats.MedianDailyTotal)
+	require.NotNil(t, stats.TypicalStartTime)
+	assert.Equal(t, klog.Ɀ_Time_(8, 30), stats.TypicalStartTime)
+	require.NotNil(t, stats.TypicalEndTime)
+	assert.Equal(t, klog.Ɀ_Time_(17, 30), stats.TypicalEndTime)
+	assert.Equal(t, 2, stats.LongestStreak)
+	assert.Equal(t, 1, stats.BusiestWeekday)
+	assert.Equal(t, klog.NewDuration(17, 0), stats.BusiestWeekdayTotal)
+	assert.Equal(t, 2.0, stats.AverageEntriesPerDay)
+	assert.Equal(t, 4, stats.RangeCount)
+	assert.Equal(t, 2, stats.DurationCount)
+	assert.Equal(t, 0, stats.OpenRangeCount)
+}
+
+func TestStatsWithShiftedTimes(t *testing.T) {
+	rs := []klog.Record{
+		func() klog.Record {
+			r := klog.NewRecord(klog.Ɀ_Date_(2024, 1, 1))
+			r.AddRange(klog.Ɀ_Range_(klog.Ɀ_TimeYesterday_(23, 0), klog.Ɀ_Time_(2, 0)), nil)
+			return r
+		}(),
+		func() klog.Record {
+			r := klog.NewRecord(klog.Ɀ_Date_(2024, 1, 2))
+			r.AddRange(klog.Ɀ_Range_(klog.Ɀ_Time_(22, 0), klog.Ɀ_TimeTomorrow_(1, 0)), nil)
+			return r
+		}(),
+		func() klog.Record {
+			r := klog.NewRecord(klog.Ɀ_Date_(2024, 1, 3))
+			r.AddRange(klog.Ɀ_Range_(klog.Ɀ_TimeYesterday_(22, 0), klog.Ɀ_TimeTomorrow_(2, 0)), nil)
+			return r
+		}(),
+	}
+	stats := NewStats(rs...)
+	assert.Equal(t, klog.Ɀ_TimeYesterday_(23, 0), stats.TypicalStartTime)
+	assert.Equal(t, klog.Ɀ_TimeTomorrow_(1, 0), stats.TypicalEndTime)
+	assert.Equal(t, 3, stats.LongestStreak)
+}
+
+func TestStatsWithOpenRange(t *testing.T) {
+	r := klog.NewRecord(klog.Ɀ_Date_(2024, 1, 1))
+	r.AddDuration(klog.NewDuration(1, 0), nil)
+	_ = r.Start(klog.NewOpenRange(klog.Ɀ_Time_(10, 0)), nil)
+	stats := NewStats(r)
+	assert.Equal(t, 1, stats.OpenRangeCount)
+	assert.Equal(t, klog.Ɀ_Time_(10, 0), stats.TypicalStartTime)
+	assert.Nil(t, stats.TypicalEndTime)
+}
@@ -3,6 +3,7 @@ package service
 import (
 	"github.com/jotaen/klog/klog"
 	"sort"
+	"strings"
 )
 
 type TagStats struct {
@@ -20,6 +21,9 @@ type TagStats struct {
 
 // AggregateTotalsByTags returns a list of tags (sorted by tag, alphanumerically)
 // that contains statistics about the tags appearing in the data.
+// Hierarchical tags (e.g. `#acme/backend`) are rolled up to their parents
+// (e.g. `#acme`). The list is sorted as a tree, i.e. every tag is directly
+// followed by its tag values, and then by its children.
 func AggregateTotalsByTags(rs ...klog.Record) ([]TagStats, TagStats) {
 	tagStats := make(totalByTag)
 	untagged := TagStats{
@@ -38,16 +42,78 @@ func AggregateTotalsByTags(rs ...klog.Record) ([]TagStats, TagStats) {
 			}
 			alreadyCounted := make(map[klog.Tag]bool)
 			for tag := range allTags.ForLookup() {
-				if alreadyCounted[tag] {
-					continue
+				for _, t := range append(tag.Parents(), tag) {
+					if alreadyCounted[t] {
+						continue
+					}
+					alreadyCounted[t] = true
+					tagStats.put(t, e.Duration())
 				}
-				tagStats.put(tag, e.Duration())
 			}
 		}
 	}
 	return tagStats.toSortedList(), untagged
 }
 
+type TagPatternStats struct {
+	Pattern klog.TagPattern
+
+	// Total is the total duration of all entries that match the pattern.
+	Total klog.Duration
+
+	// Count is the total number of entries that match the pattern.
+	Count int
+
+	// Tags contains the statistics of the individual tags that match the
+	// pattern (sorted by tag, alphanumerically). If the pattern has a value,
+	// these are the tags with value, otherwise the base tags.
+	Tags []TagStats
+}
+
+// AggregateTotalsByTagPatterns returns a list of statistics about the entries
+// that match the tag patterns, in the same order as the patterns. An entry
+// is counted only once per pattern, even if several of its tags match. Like
+// in AggregateTotalsByTags, hierarchical tags are rolled up to their parents.
+func AggregateTotalsByTagPatterns(patterns []klog.TagPattern, rs ...klog.Record) []TagPatternStats {
+	result := make([]TagPatternStats, len(patterns))
+	for i, p := range patterns {
+		result[i] = TagPatternStats{Pattern: p, Total: klog.NewDuration(0, 0)}
+	}
+	for _, r := range rs {
+		for _, e := range r.Entries() {
+			allTags := klog.Merge(r.Summary().Tags(), e.Summary().Tags())
+			for i, p := range patterns {
+				if containsMatchOrParent(&allTags, p) {
+					result[i].Total = result[i].Total.Plus(e.Duration())
+					result[i].Count++
+				}
+			}
+		}
+	}
+	tagStats, _ := AggregateTotalsByTags(rs...)
+	for i, p := range patterns {
+		for _, t := range tagStats {
+			if (t.Tag.Value() == "") == (p.Value() == "") && p.Matches(t.Tag) {
+				result[i].Tags = append(result[i].Tags, t)
+			}
+		}
+	}
+	return result
+}
+
+// containsMatchOrParent checks whether any of the tags, or any of their
+// parents, matches the pattern.
+func containsMatchOrParent(ts *klog.TagSet, p klog.TagPattern) bool {
+	for t := range ts.ForLookup() {
+		for _, x := range append(t.Parents(), t) {
+			if p.Matches(x) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 // Structure: "tagName":"tagValue":TagStats
 type totalByTag map[string]map[string]*TagStats
 
@@ -61,7 +127,7 @@ func (tbt totalByTag) put(t klog.Tag, d klog.Duration) {
 			Tag:        t,
 			Total:      klog.NewDuration(0, 0),
 			Count:      0,
-			keyForSort: t.Name() + "=" + t.Value(),
+			keyForSort: sortKey(t),
 		}
 	}
 
@@ -82,3 +148,12 @@ func (tbt totalByTag) toSortedList() []TagStats {
 	})
 	return result
 }
+
+// sortKey yields a key that sorts tags alphanumerically, but such that parent
+// tags precede their tag values, which in turn precede their child tags. The
+// separator is substituted by `>`, which directly follows `=`, so that the
+// values come first, and no other tag can sort in between. Tags without
+// separator sort in the same way as without hierarchy.
+func sortKey(t klog.Tag) string {
+	return strings.ReplaceAll(t.Name(), klog.TagSeparator, ">") + "=" + t.Value()
+}
@@ -1,6 +1,7 @@
 package service
 
 import (
+	"fmt"
 	"github.com/jotaen/klog/klog"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -109,3 +110,92 @@ func TestAggregateTotalTimesByTagSortsAlphabetically(t *testing.T) {
 	i += 1
 	assert.Equal(t, klog.NewTagOrPanic("ddd", ""), tagStats[i].Tag)
 }
+
+func TestAggregateTotalTimesByTagPattern(t *testing.T) {
+	rs := []klog.Record{
+		func() klog.Record {
+			r := klog.NewRecord(klog.Ɀ_Date_(2020, 1, 1))
+			r.SetSummary(klog.Ɀ_RecordSummary_("#client-acme"))
+			r.AddDuration(klog.NewDuration(1, 0), klog.Ɀ_EntrySummary_("#ticket=PROJ-1"))
+			r.AddDuration(klog.NewDuration(2, 0), klog.Ɀ_EntrySummary_("#client-globex #ticket=OTHER-1"))
+			return r
+		}(),
+		func() klog.Record {
+			r := klog.NewRecord(klog.Ɀ_Date_(2020, 1, 2))
+			r.AddDuration(klog.NewDuration(4, 0), klog.Ɀ_EntrySummary_("#client-globex #ticket=PROJ-2"))
+			r.AddDuration(klog.NewDuration(8, 0), klog.Ɀ_EntrySummary_("no tag"))
+			return r
+		}(),
+	}
+
+	stats := AggregateTotalsByTagPatterns([]klog.TagPattern{
+		klog.NewTagPatternOrPanic("client-*", ""),
+		klog.NewTagPatternOrPanic("ticket", "PROJ-*"),
+		klog.NewTagPatternOrPanic("foo*", ""),
+	}, rs...)
+	require.Len(t, stats, 3)
+
+	// Entries with multiple matching tags are only counted once.
+	assert.Equal(t, klog.NewTagPatternOrPanic("client-*", ""), stats[0].Pattern)
+	assert.Equal(t, klog.NewDuration(7, 0), stats[0].Total)
+	assert.Equal(t, 3, stats[0].Count)
+	require.Len(t, stats[0].Tags, 2)
+	assert.Equal(t, klog.NewTagOrPanic("client-acme", ""), stats[0].Tags[0].Tag)
+	assert.Equal(t, klog.NewDuration(3, 0), stats[0].Tags[0].Total)
+	assert.Equal(t, klog.NewTagOrPanic("client-globex", ""), stats[0].Tags[1].Tag)
+	assert.Equal(t, klog.NewDuration(6, 0), stats[0].Tags[1].Total)
+
+	assert.Equal(t, klog.NewDuration(5, 0), stats[1].Total)
+	assert.Equal(t, 2, stats[1].Count)
+	require.Len(t, stats[1].Tags, 2)
+	assert.Equal(t, klog.NewTagOrPanic("ticket", "PROJ-1"), stats[1].Tags[0].Tag)
+	assert.Equal(t, klog.NewTagOrPanic("ticket", "PROJ-2"), stats[1].Tags[1].Tag)
+
+	assert.Equal(t, klog.NewDuration(0, 0), stats[2].Total)
+	assert.Equal(t, 0, stats[2].Count)
+	assert.Len(t, stats[2].Tags, 0)
+}
+
+func TestAggregateTotalTimesByHierarchicalTags(t *testing.T) {
+	r := klog.NewRecord(klog.Ɀ_Date_(2020, 1, 1))
+	r.AddDuration(klog.NewDuration(1, 0), klog.Ɀ_EntrySummary_("#acme-corp"))
+	r.AddDuration(klog.NewDuration(2, 0), klog.Ɀ_EntrySummary_("#acme/backend/api"))
+	r.AddDuration(klog.NewDuration(3, 0), klog.Ɀ_EntrySummary_("#acme/backend=x"))
+	r.AddDuration(klog.NewDuration(4, 0), klog.Ɀ_EntrySummary_("#acme/frontend #acme/backend"))
+	r.AddDuration(klog.NewDuration(5, 0), klog.Ɀ_EntrySummary_("#acme=y"))
+
+	tagStats, _ := AggregateTotalsByTags(r)
+	var actual []string
+	for _, s := range tagStats {
+		actual = append(actual, s.Tag.ToString()+" "+s.Total.ToString()+" "+fmt.Sprint(s.Count))
+	}
+	assert.Equal(t, []string{
+		// Flat tags sort as before, i.e. `-` precedes the end of the tag name.
+		"#acme-corp 1h 1",
+		// Every entry is counted once for a parent, even if several child tags match.
+		"#acme 14h 4",
+		"#acme=y 5h 1",
+		"#acme/backend 9h 3",
+		"#acme/backend=x 3h 1",
+		"#acme/backend/api 2h 1",
+		"#acme/frontend 4h 1",
+	}, actual)
+}
+
+func TestAggregateTotalTimesByTagPatternWithHierarchicalTags(t *testing.T) {
+	r := klog.NewRecord(klog.Ɀ_Date_(2020, 1, 1))
+	r.AddDuration(klog.NewDuration(1, 0), klog.Ɀ_EntrySummary_("#acme/backend"))
+	r.AddDuration(klog.NewDuration(2, 0), klog.Ɀ_EntrySummary_("#acme"))
+	r.AddDuration(klog.NewDuration(4, 0), klog.Ɀ_EntrySummary_("#acme-corp"))
+
+	stats := AggregateTotalsByTagPatterns([]klog.TagPattern{
+		klog.NewTagPatternOrPanic("acme", ""),
+	}, r)
+	require.Len(t, stats, 1)
+	assert.Equal(t, klog.NewDuration(3, 0), stats[0].Total)
+	assert.Equal(t, 2, stats[0].Count)
+	require.Len(t, stats[0].Tags, 1)
+	assert.Equal(t, klog.NewTagOrPanic("acme", ""), stats[0].Tags[0].Tag)
+	assert.Equal(t, stats[0].Total, stats[0].Tags[0].Total)
+	assert.Equal(t, stats[0].Count, stats[0].Tags[0].Count)
+}
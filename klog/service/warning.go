@@ -24,6 +24,17 @@ var (
 	}
 )
 
+const skippedImportWarningName = "SKIPPED_IMPORT"
+
+// NewSkippedImportWarning creates a warning about input data that couldn’t
+// be imported, where the message describes the data and the reason.
+func NewSkippedImportWarning(message string) UsageWarning {
+	return UsageWarning{
+		Name:    skippedImportWarningName,
+		Message: message,
+	}
+}
+
 type checker interface {
 	Warn(klog.Record) klog.Date
 	Message() string
@@ -42,6 +53,7 @@ func NewDisabledCheckers() DisabledCheckers {
 		(&moreThan24HoursChecker{}).Name():       false,
 		PointlessNowWarning.Name:                 false,
 		EntryFilteredDiffWarning.Name:            false,
+		skippedImportWarningName:                 false,
 	}
 }
 
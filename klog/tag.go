@@ -6,7 +6,10 @@ import (
 	"strings"
 )
 
-var HashTagPattern = regexp.MustCompile(`#([\p{L}\d_-]+)(=(("[^"]*")|('[^']*')|([\p{L}\d_-]*)))?`)
+// TagSeparator separates the levels of hierarchical tag names, e.g. `#acme/backend/api`.
+const TagSeparator = "/"
+
+var HashTagPattern = regexp.MustCompile(`#([\p{L}\d_-]+(?:/[\p{L}\d_-]+)*)(=(("[^"]*")|('[^']*')|([\p{L}\d_-]*)))?`)
 var unquotedValuePattern = regexp.MustCompile(`^[\p{L}\d_-]+$`)
 
 type Tag struct {
@@ -59,6 +62,18 @@ func (t Tag) Value() string {
 	return t.value
 }
 
+// Parents returns the ancestors of a hierarchical tag (without value), from
+// the top level downwards. E.g., the parents of `#acme/backend/api=1` are
+// `#acme` and `#acme/backend`.
+func (t Tag) Parents() []Tag {
+	var parents []Tag
+	segments := strings.Split(t.name, TagSeparator)
+	for i := 1; i < len(segments); i++ {
+		parents = append(parents, Tag{strings.Join(segments[:i], TagSeparator), ""})
+	}
+	return parents
+}
+
 func (t Tag) ToString() string {
 	result := "#" + t.name
 	if t.value != "" {
@@ -76,6 +91,92 @@ func (t Tag) ToString() string {
 	return result
 }
 
+// TagPattern matches tags by name and value, where the wildcard `*` stands
+// for any sequence of characters, e.g. `#client-*` or `#ticket=PROJ-*`.
+// Similar to tags, a pattern without value matches regardless of the tag’s
+// value, whereas a pattern with value only matches tags with a value.
+type TagPattern struct {
+	name  string
+	value string
+}
+
+var hashTagPatternPattern = regexp.MustCompile(`^#([\p{L}\d_*-]+(?:/[\p{L}\d_*-]+)*)(=(("[^"]*")|('[^']*')|([\p{L}\d_*-]*)))?$`)
+
+func NewTagPatternFromString(pattern string) (TagPattern, error) {
+	if !strings.HasPrefix(pattern, "#") {
+		pattern = "#" + pattern
+	}
+	match := hashTagPatternPattern.FindStringSubmatch(pattern)
+	if match == nil {
+		return TagPattern{}, errors.New("INVALID_TAG_PATTERN")
+	}
+	value := match[3]
+	if strings.HasPrefix(value, `"`) || strings.HasPrefix(value, `'`) {
+		value = value[1 : len(value)-1]
+	}
+	return TagPattern{strings.ToLower(match[1]), value}, nil
+}
+
+// NewTagPatternOrPanic constructs a new tag pattern but will panic if the
+// parameters don’t yield a valid pattern.
+func NewTagPatternOrPanic(name string, value string) TagPattern {
+	if strings.Contains(value, "\"") && strings.Contains(value, "'") {
+		panic("Invalid tag pattern")
+	}
+	return TagPattern{strings.ToLower(name), value}
+}
+
+func (p TagPattern) Name() string {
+	return p.name
+}
+
+func (p TagPattern) Value() string {
+	return p.value
+}
+
+// Matches checks whether the tag satisfies the pattern.
+func (p TagPattern) Matches(t Tag) bool {
+	if !matchWildcards(p.name, t.name) {
+		return false
+	}
+	if p.value == "" {
+		return true
+	}
+	return t.value != "" && matchWildcards(p.value, t.value)
+}
+
+func (p TagPattern) ToString() string {
+	if p.value == "" || unquotedValuePattern.MatchString(strings.ReplaceAll(p.value, "*", "")) {
+		result := "#" + p.name
+		if p.value != "" {
+			result += "=" + p.value
+		}
+		return result
+	}
+	return NewTagOrPanic(p.name, p.value).ToString()
+}
+
+// matchWildcards checks whether the text matches the pattern, where `*` in
+// the pattern matches any sequence of characters (including none).
+func matchWildcards(pattern string, text string) bool {
+	parts := strings.Split(pattern, "*")
+	if len(parts) == 1 {
+		return pattern == text
+	}
+	if !strings.HasPrefix(text, parts[0]) {
+		return false
+	}
+	text = text[len(parts[0]):]
+	for _, part := range parts[1 : len(parts)-1] {
+		i := strings.Index(text, part)
+		if i == -1 {
+			return false
+		}
+		text = text[i+len(part):]
+	}
+	return strings.HasSuffix(text, parts[len(parts)-1])
+}
+
 type TagSet struct {
 	lookup   map[Tag]bool
 	original []Tag
@@ -102,6 +203,17 @@ func (ts *TagSet) Contains(tag Tag) bool {
 	return ts.lookup[tag]
 }
 
+// ContainsMatch checks whether the TagSet contains a tag that matches
+// the given pattern.
+func (ts *TagSet) ContainsMatch(p TagPattern) bool {
+	for t := range ts.lookup {
+		if p.Matches(t) {
+			return true
+		}
+	}
+	return false
+}
+
 // IsEmpty checks whether the TagSet contains something or not.
 func (ts *TagSet) IsEmpty() bool {
 	return len(ts.lookup) == 0
@@ -18,6 +18,8 @@ func TestCreatesNewTag(t *testing.T) {
 		{"#t1a2g3", "t1a2g3"},
 		{"#---", "---"},
 		{"#___", "___"},
+		{"#acme/backend", "acme/backend"},
+		{"#Acme/Backend/API", "acme/backend/api"},
 	} {
 		tag, err := NewTagFromString(x.tag)
 		require.Nil(t, err)
@@ -107,6 +109,9 @@ func TestRejectsInvalidTags(t *testing.T) {
 		"#tag:tag",
 		"#tag!!!",
 		"#t-a?g",
+		"#/tag",
+		"#tag/",
+		"#tag//tag",
 		`#tag=foo=bar`,
 		`#tag='foo`,
 		`#tag='It's great'`,
@@ -181,3 +186,133 @@ func TestTagSet(t *testing.T) {
 	assert.True(t, ts.Contains(NewTagOrPanic("foo", "")))
 	assert.Equal(t, []string{"#test", "#project=value", "#foo", "#foo"}, ts.ToStrings())
 }
+
+func TestTagParents(t *testing.T) {
+	assert.Nil(t, NewTagOrPanic("acme", "").Parents())
+	assert.Equal(t, []Tag{
+		NewTagOrPanic("acme", ""),
+	}, NewTagOrPanic("acme/backend", "").Parents())
+	assert.Equal(t, []Tag{
+		NewTagOrPanic("acme", ""),
+		NewTagOrPanic("acme/backend", ""),
+	}, NewTagOrPanic("acme/backend/api", "1").Parents())
+}
+
+func TestTagSetWithHierarchicalTags(t *testing.T) {
+	ts := NewEmptyTagSet()
+	ts.Put(NewTagOrPanic("acme/backend/api", "1"))
+	assert.True(t, ts.Contains(NewTagOrPanic("acme/backend/api", "1")))
+	assert.True(t, ts.Contains(NewTagOrPanic("acme/backend/api", "")))
+	// Parents are not part of the TagSet, they are only rolled up when
+	// aggregating or filtering.
+	assert.False(t, ts.Contains(NewTagOrPanic("acme/backend", "")))
+	assert.False(t, ts.Contains(NewTagOrPanic("acme", "")))
+	assert.False(t, ts.Contains(NewTagOrPanic("backend", "")))
+	assert.False(t, ts.Contains(NewTagOrPanic("acme/backend/api/v2", "")))
+	assert.False(t, ts.Contains(NewTagOrPanic("acme/frontend", "")))
+	assert.True(t, ts.ContainsMatch(NewTagPatternOrPanic("acme/*", "")))
+	assert.Equal(t, []string{"#acme/backend/api=1"}, ts.ToStrings())
+}
+
+func TestCreatesNewTagPattern(t *testing.T) {
+	for _, x := range []struct {
+		pattern string
+		expect  TagPattern
+	}{
+		{"#tag", NewTagPatternOrPanic("tag", "")},
+		{"tag", NewTagPatternOrPanic("tag", "")},
+		{"#Client-*", NewTagPatternOrPanic("client-*", "")},
+		{"#*", NewTagPatternOrPanic("*", "")},
+		{"#ticket=PROJ-*", NewTagPatternOrPanic("ticket", "PROJ-*")},
+		{"#ticket='PROJ *'", NewTagPatternOrPanic("ticket", "PROJ *")},
+		{`#ticket="*"`, NewTagPatternOrPanic("ticket", "*")},
+		{"#acme/*", NewTagPatternOrPanic("acme/*", "")},
+		{"#*/api", NewTagPatternOrPanic("*/api", "")},
+	} {
+		p, err := NewTagPatternFromString(x.pattern)
+		require.Nil(t, err, x.pattern)
+		assert.Equal(t, x.expect, p, x.pattern)
+	}
+}
+
+func TestRejectsInvalidTagPatterns(t *testing.T) {
+	for _, pattern := range []string{
+		"",
+		"#",
+		"#foo?",
+		"#foo bar",
+		"#foo=bar baz",
+		"#foo='bar",
+	} {
+		_, err := NewTagPatternFromString(pattern)
+		require.Error(t, err, pattern)
+	}
+}
+
+func TestSerialiseTagPattern(t *testing.T) {
+	assert.Equal(t, "#client-*", NewTagPatternOrPanic("client-*", "").ToString())
+	assert.Equal(t, "#ticket=PROJ-*", NewTagPatternOrPanic("ticket", "PROJ-*").ToString())
+	assert.Equal(t, `#ticket="PROJ *"`, NewTagPatternOrPanic("ticket", "PROJ *").ToString())
+}
+
+func TestTagPatternMatching(t *testing.T) {
+	for _, x := range []struct {
+		pattern string
+		tag     string
+		matches bool
+	}{
+		// Without wildcards, patterns behave like tags.
+		{"#foo", "#foo", true},
+		{"#foo", "#FOO", true},
+		{"#foo", "#foo=bar", true},
+		{"#foo=bar", "#foo=bar", true},
+		{"#foo=bar", "#foo", false},
+		{"#foo=bar", "#foo=BAR", false},
+		{"#foo", "#foobar", false},
+
+		// With wildcards in the name.
+		{"#client-*", "#client-acme", true},
+		{"#client-*", "#client-", true},
+		{"#client-*", "#client-acme=1", true},
+		{"#client-*", "#client", false},
+		{"#client-*", "#other-client-acme", false},
+		{"#*-acme", "#client-acme", true},
+		{"#c*-*e", "#client-acme", true},
+		{"#c*-*e", "#client-acmes", false},
+		{"#*", "#anything", true},
+
+		// The separator of hierarchical tags is a regular character.
+		{"#acme/*", "#acme/backend", true},
+		{"#acme/*", "#acme/backend/api", true},
+		{"#acme/*", "#acme", false},
+		{"#*/api", "#acme/backend/api", true},
+		{"#acme*", "#acme/backend", true},
+
+		// With wildcards in the value.
+		{"#ticket=PROJ-*", "#ticket=PROJ-123", true},
+		{"#ticket=PROJ-*", "#ticket=PROJ-", true},
+		{"#ticket=PROJ-*", "#ticket=proj-123", false},
+		{"#ticket=PROJ-*", "#ticket", false},
+		{"#ticket=*", "#ticket=1", true},
+		{"#ticket=*", "#ticket", false},
+		{"#*=*1", "#ticket=1", true},
+		{"#*=*1", "#ticket=2", false},
+	} {
+		p, pErr := NewTagPatternFromString(x.pattern)
+		require.Nil(t, pErr)
+		tag, tErr := NewTagFromString(x.tag)
+		require.Nil(t, tErr)
+		assert.Equal(t, x.matches, p.Matches(tag), x.pattern+" "+x.tag)
+	}
+}
+
+func TestTagSetContainsMatch(t *testing.T) {
+	ts := NewEmptyTagSet()
+	ts.Put(NewTagOrPanic("client-acme", ""))
+	ts.Put(NewTagOrPanic("ticket", "PROJ-1"))
+	assert.True(t, ts.ContainsMatch(NewTagPatternOrPanic("client-*", "")))
+	assert.True(t, ts.ContainsMatch(NewTagPatternOrPanic("ticket", "PROJ-*")))
+	assert.True(t, ts.ContainsMatch(NewTagPatternOrPanic("tick*", "")))
+	assert.False(t, ts.ContainsMatch(NewTagPatternOrPanic("client-*", "*")))
+	assert.False(t, ts.ContainsMatch(NewTagPatternOrPanic("project-*", "")))
+}